@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,6 +26,10 @@ import (
 
 type StorageType string
 
+type DatacenterAvailability string
+
+type DeletionPolicy string
+
 const (
 	DefaultReaperImage = "thelastpickle/cassandra-reaper:2.0.5"
 
@@ -33,16 +38,180 @@ const (
 
 	DefaultKeyspace    = "reaper_db"
 	DefaultStorageType = StorageTypeMemory
+
+	DatacenterAvailabilityAll     = DatacenterAvailability("ALL")
+	DatacenterAvailabilityLocal   = DatacenterAvailability("LOCAL")
+	DatacenterAvailabilityEach    = DatacenterAvailability("EACH")
+	DatacenterAvailabilitySidecar = DatacenterAvailability("SIDECAR")
+
+	DefaultDatacenterAvailability = DatacenterAvailabilityAll
+
+	DeletionPolicyRetain = DeletionPolicy("Retain")
+	DeletionPolicyDelete = DeletionPolicy("Delete")
+
+	DefaultDeletionPolicy = DeletionPolicyRetain
+
+	DefaultAuthProviderType = "plainText"
+
+	DefaultSchemaJobImage           = "jsanda/create_keyspace:latest"
+	DefaultSchemaJobImagePullPolicy = corev1.PullIfNotPresent
+	DefaultSchemaJobBackoffLimit    = 3
+	DefaultSchemaJobMaxAttempts     = 5
+	DefaultAlterKeyspaceImage       = "cassandra:3.11.7"
+
+	DefaultHangingRepairTimeoutMins      = 30
+	DefaultRepairIntensity               = "0.9"
+	DefaultRepairParallelism             = "DATACENTER_AWARE"
+	DefaultRepairRunThreadCount          = 15
+	DefaultScheduleDaysBetween           = 7
+	DefaultEnableCrossOrigin             = true
+	DefaultEnableDynamicSeedList         = false
+	DefaultReplicas                      = 1
+	DefaultJmxConnectionTimeoutInSeconds = 20
+	DefaultSegmentCountPerNode           = 16
+
+	DefaultAutoSchedulingInitialDelayPeriod      = "PT15S"
+	DefaultAutoSchedulingPeriodBetweenPolls      = "PT10M"
+	DefaultAutoSchedulingTimeBeforeFirstSchedule = "PT5M"
+	DefaultAutoSchedulingScheduleSpreadPeriod    = "PT6H"
 )
 
 type ServerConfig struct {
+	// Configures Reaper to automatically create repair schedules for the keyspaces of every
+	// registered cluster.
+	AutoScheduling AutoScheduling `json:"autoScheduling,omitempty" yaml:"autoScheduling,omitempty"`
+
 	StorageType StorageType `json:"storageType,omitempty"`
 
 	CassandraBackend *CassandraBackend `json:"cassandraBackend,omitempty" yaml:"cassandra,omitempty"`
 
+	// Controls which nodes Reaper connects to through JMX in multi-DC clusters. With ALL,
+	// Reaper connects to the nodes of every data center. With LOCAL, it only connects to the
+	// nodes of its own data center and repairs the others through them. With EACH, the
+	// operator runs a Reaper deployment per data center of the backend keyspace's
+	// NetworkTopologyStrategy replication, and each instance only connects to the nodes of its
+	// data center. With SIDECAR, the operator injects a Reaper container into the pods of the
+	// CassandraDatacenters that reference the Reaper instead of running a deployment, and each
+	// instance only connects to its own node. The Reaper has to be in the namespace of the
+	// CassandraDatacenters. EACH and SIDECAR require the cassandra StorageType.
+	//
+	// Defaults to ALL
+	// +kubebuilder:validation:Enum=ALL;LOCAL;EACH;SIDECAR
+	// +optional
+	DatacenterAvailability DatacenterAvailability `json:"datacenterAvailability,omitempty" yaml:"datacenterAvailability,omitempty"`
+
 	// Defines the username and password that Reaper will use to authenticate JMX connections to Cassandra
 	// clusters. These credentials need to be stored on each Cassandra node.
 	JmxUserSecretName string `json:"jmxUserSecretName,omitempty"`
+
+	// Configures encryption for the CQL connections to the Cassandra backend and for the JMX
+	// connections to the Cassandra nodes.
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty" yaml:"-"`
+
+	// The amount of time in minutes to wait for a single repair to finish. Defaults to 30. If this timeout is reached,
+	// the repair segment in question will be cancelled, if possible, and then scheduled for later repair again within
+	// the same repair run process.
+	HangingRepairTimeoutMins *int32 `json:"hangingRepairTimeoutMins,omitempty" yaml:"hangingRepairTimeoutMins,omitempty"`
+
+	// Sets the default repair type unless specifically defined for each run. Note that this is only supported with the
+	// PARALLEL repairParallelism setting.
+	//
+	// Note: It is recommended to avoid using incremental repair before Cassandra 4.0 as subtle bugs can lead to
+	// overstreaming and cluster instabililty
+	IncrementalRepair bool `json:"incrementalRepair,omitempty" yaml:"incrementalRepair"`
+
+	// Repair intensity defines the amount of time to sleep between triggering each repair segment while running a
+	// repair run. When intensity is 1.0, it means that Reaper doesn't sleep at all before triggering next segment, and
+	// otherwise the sleep time is defined by how much time it took to repair the last segment divided by the intensity
+	// value. The value must be greater than 0 and at most 1.
+	//
+	// Defaults to 0.9.
+	RepairIntensity string `json:"repairIntensity,omitempty" yaml:"repairIntensity,omitempty"`
+
+	// Type of parallelism to apply by default to repair runs. The value must be either SEQUENTIAL, PARALLEL, or
+	// DATACENTER_AWARE.
+	//
+	// Defaults to DATACENTER_AWARE
+	RepairParallelism string `json:"repairParallelism,omitempty" yaml:"repairParallelism,omitempty"`
+
+	// The amount of threads to use for handling the Reaper tasks. Have this big enough not to cause blocking in cause
+	// some thread is waiting for I/O, like calling a Cassandra cluster through JMX.
+	//
+	// Defaults to 15
+	RepairRunThreadCount *int32 `json:"repairRunThreadCount,omitempty" yaml:"repairRunThreadCount,omitempty"`
+
+	// Defines the amount of days to wait between scheduling new repairs. The value configured here is the default for
+	// new repair schedules, but you can also define it separately for each new schedule. Using value 0 for continuous
+	// repairs is also supported.
+	//
+	// Defaults to 7
+	ScheduleDaysBetween *int32 `json:"scheduleDaysBetween,omitempty" yaml:"scheduleDaysBetween,omitempty"`
+
+	// Optional setting which can be used to enable the CORS headers for running an external GUI application. When
+	// enabled it will allow REST requests incoming from other origins than the domain that hosts Reaper.
+	//
+	// Defaults to true
+	EnableCrossOrigin *bool `json:"enableCrossOrigin,omitempty" yaml:"enableCrossOrigin,omitempty"`
+
+	// Allow Reaper to add all nodes in the cluster as contact points when adding a new cluster, instead of just
+	// adding the provided node.
+	//
+	// Defaults to false, or to true when replicas is greater than 1
+	EnableDynamicSeedList *bool `json:"enableDynamicSeedList,omitempty" yaml:"enableDynamicSeedList,omitempty"`
+
+	// Disables repairs of any tables that use either the TimeWindowCompactionStrategy or DateTieredCompactionStrategy.
+	//
+	// Defaults to false
+	BlacklistTwcsTables bool `json:"blacklistTwcsTables,omitempty" yaml:"blacklistTwcsTables"`
+
+	// Controls the timeout for establishing JMX connections. The value should be low enough to avoid stalling simple
+	// operations in multi region clusters, but high enough to allow connections under normal conditions.
+	//
+	// Defaults to 20
+	JmxConnectionTimeoutInSeconds *int32 `json:"jmxConnectionTimeoutInSeconds,omitempty" yaml:"jmxConnectionTimeoutInSeconds,omitempty"`
+
+	// Defines the default amount of repair segments to create for newly registered Cassandra repair runs, for each
+	// node in the cluster. This value can be overwritten when executing a repair run via Reaper.
+	//
+	// Defaults to 16
+	SegmentCountPerNode *int32 `json:"segmentCountPerNode,omitempty" yaml:"segmentCountPerNode,omitempty"`
+}
+
+type TLSConfig struct {
+	// Enables client-to-node encryption for the CQL connections to the Cassandra backend. The
+	// schema job is configured with the same setting.
+	CQLEnabled bool `json:"cqlEnabled,omitempty"`
+
+	// Enables SSL for the JMX connections to the Cassandra nodes.
+	JMXEnabled bool `json:"jmxEnabled,omitempty"`
+
+	// A secret with the TLS material. It must have the truststore.jks and truststore-password
+	// keys. The keystore.jks and keystore-password keys are used for client certificate
+	// authentication when present. The schema job connects with the PEM encoded ca.crt, and
+	// tls.crt and tls.key when present, so ca.crt is required when CQLEnabled is set and the
+	// Cassandra backend is used.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+}
+
+type AutoScheduling struct {
+	// Enables or disables auto scheduling
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+
+	// The duration of the delay before the schedule period starts. Defaults to PT15S (15 seconds)
+	InitialDelayPeriod string `json:"initialDelayPeriod,omitempty" yaml:"initialDelayPeriod,omitempty"`
+
+	// The amount of time to wait before checking whether or not to start a repair task. Defaults to PT10M (10 minutes)
+	PeriodBetweenPolls string `json:"periodBetweenPolls,omitempty" yaml:"periodBetweenPolls,omitempty"`
+
+	// Grace period before the first repair in the schedule is started. Defaults to PT5M (5 minutes)
+	TimeBeforeFirstSchedule string `json:"timeBeforeFirstSchedule,omitempty" yaml:"timeBeforeFirstSchedule,omitempty"`
+
+	// The time spacing between each of the repair schedules that is to be carried out. Defaults to PT6H (6 hours)
+	ScheduleSpreadPeriod string `json:"scheduleSpreadPeriod,omitempty" yaml:"scheduleSpreadPeriod,omitempty"`
+
+	// The keyspaces that are to be excluded from the repair schedule.
+	ExcludedKeyspaces []string `json:"excludedKeyspaces,omitempty" yaml:"excludedKeyspaces,omitempty"`
 }
 
 // Specifies the replication strategy for a keyspace
@@ -57,25 +226,110 @@ type ReplicationConfig struct {
 type AuthProvider struct {
 	Type string `json:"type,omitempty" yaml:"type,omitempty"`
 
+	// A secret with username and password keys that hold the credentials Reaper and the schema
+	// job use to connect to the Cassandra backend.
+	// +optional
+	SecretRef corev1.LocalObjectReference `json:"secretRef,omitempty" yaml:"-"`
+
+	// Deprecated: use SecretRef instead. Only used when SecretRef is not set.
 	Username string `json:"username,omitempty" yaml:"username,omitempty"`
 
+	// Deprecated: use SecretRef instead. Only used when SecretRef is not set.
 	Password string `json:"password,omitempty" yaml:"password,omitempty"`
 }
 
 type CassandraBackend struct {
-	ClusterName string `json:"clusterName" yaml:"clusterName"`
+	// A CassandraDatacenter managed by cass-operator that hosts the backend. The cluster name,
+	// the contact points and the replication are derived from it unless they are set.
+	// +optional
+	CassandraDatacenterRef *CassandraDatacenterReference `json:"cassandraDatacenterRef,omitempty" yaml:"-"`
 
-	// The headless service that provides endpoints for the StorageTypeCassandra pods
-	CassandraService string `json:"cassandraService" yaml:"contactPoints"`
+	// Required unless CassandraDatacenterRef is set.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty" yaml:"clusterName"`
+
+	// The headless service that provides endpoints for the StorageTypeCassandra pods. Required
+	// unless CassandraDatacenterRef is set.
+	// +optional
+	CassandraService string `json:"cassandraService,omitempty" yaml:"contactPoints"`
 
 	// Defaults to reaper
 	Keyspace string `json:"keyspace,omitempty" yaml:"keyspace,omitempty"`
 
-	Replication ReplicationConfig `json:"replication" yaml:"-"`
+	// Defaults to SimpleStrategy with a replication factor of 1. When CassandraDatacenterRef is
+	// set, it defaults to NetworkTopologyStrategy with a replication factor of min(3, size) for
+	// each data center of the cluster.
+	// +optional
+	Replication ReplicationConfig `json:"replication,omitempty" yaml:"-"`
 
 	AuthProvider AuthProvider `json:"authProvider,omitempty" yaml:"authProvider,omitempty"`
 }
 
+// Identifies a CassandraDatacenter. If Namespace is empty, the namespace of the Reaper is used.
+type CassandraDatacenterReference struct {
+	Name string `json:"name"`
+
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Customizes the pods of the Reaper deployment.
+type ReaperPodTemplate struct {
+	// Labels that are added to the pods. They cannot override the labels that the operator
+	// sets.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations that are added to the pods.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Compute resources of the Reaper container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Security attributes of the pod.
+	// +optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// Security attributes of the Reaper container.
+	// +optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// Configures the service through which Reaper is reached.
+type ReaperService struct {
+	// Defaults to ClusterIP.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Annotations that are added to the service, e.g., to request an internal load balancer.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Exposes Reaper's admin port, 8081, which serves the health check and metrics endpoints.
+	// +optional
+	ExposeAdminPort bool `json:"exposeAdminPort,omitempty"`
+}
+
 // ReaperSpec defines the desired state of Reaper
 type ReaperSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -83,9 +337,160 @@ type ReaperSpec struct {
 
 	Image string `json:"image,omitempty"`
 
+	// The number of Reaper pods. More than one replica requires the Cassandra backend in which
+	// case the Reaper instances run in distributed mode and coordinate through the backend.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// +optional
+	PodTemplate ReaperPodTemplate `json:"podTemplate,omitempty"`
+
+	// +optional
+	Service ReaperService `json:"service,omitempty"`
+
+	// Configures the job that creates the Reaper keyspace in the Cassandra backend.
+	// +optional
+	SchemaJob ReaperSchemaJob `json:"schemaJob,omitempty"`
+
+	// Enables authentication for Reaper's web UI and REST API. Authentication is disabled when
+	// this is not set.
+	// +optional
+	UIAuth *ReaperUIAuth `json:"uiAuth,omitempty"`
+
+	// Exposes Reaper's admin port through the service and, when the monitoring.coreos.com CRDs
+	// of the Prometheus Operator are installed, creates a ServiceMonitor that scrapes Reaper's
+	// metrics and a PrometheusRule with alerts for Reaper being down, repairs that make no
+	// progress and segments that keep failing. Monitoring is disabled when this is not set.
+	// +optional
+	Monitoring *ReaperMonitoring `json:"monitoring,omitempty"`
+
+	// Controls what happens to the Reaper keyspace in the Cassandra backend when the Reaper is
+	// deleted. With Delete, the operator runs a job with the schemaJob's alterKeyspaceImage
+	// that drops the keyspace before the Reaper is removed. With Retain, the keyspace is kept.
+	// Either way, the clusters in .status.clusters are unregistered from Reaper first.
+	//
+	// Defaults to Retain
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
 	ServerConfig ServerConfig `json:"serverConfig,omitempty" yaml:"serverConfig,omitempty"`
 }
 
+type ReaperSchemaJob struct {
+	// The image of the job. Defaults to jsanda/create_keyspace:latest. Setting an image with a
+	// fixed tag is recommended.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Defaults to IfNotPresent.
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// The image of the job that alters the replication of the keyspace when it changes. The
+	// image has to provide cqlsh. Defaults to cassandra:3.11.7.
+	// +optional
+	AlterKeyspaceImage string `json:"alterKeyspaceImage,omitempty"`
+
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// The number of times the job's pod is retried before the job is marked as failed.
+	// Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// The number of times a failed job is recreated before the operator gives up. The delay
+	// before each attempt doubles. The attempts are counted again after the Reaper spec is
+	// changed. Defaults to 5.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+}
+
+type ReaperUIAuth struct {
+	// A secret with username and password keys. Reaper requires these credentials for its web
+	// UI and REST API, and the operator logs in with them when it calls the REST API.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+}
+
+type ReaperMonitoring struct {
+	// Labels that are added to the ServiceMonitor and the PrometheusRule, e.g., to match the
+	// selectors of a Prometheus.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// The interval at which Prometheus scrapes Reaper's metrics, e.g., 30s. Defaults to the
+	// scrape interval of the Prometheus.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Skips the PrometheusRule with the default alerts.
+	// +optional
+	DisableAlerts bool `json:"disableAlerts,omitempty"`
+}
+
+type ReaperConditionType string
+
+const (
+	// The Cassandra backend keyspace has been created. This is true when the memory backend
+	// is used.
+	SchemaInitialized ReaperConditionType = "SchemaInitialized"
+
+	// The Reaper deployment has the expected number of ready pods.
+	DeploymentAvailable ReaperConditionType = "DeploymentAvailable"
+
+	// The service through which Reaper's REST API is reached exists.
+	ServiceReady ReaperConditionType = "ServiceReady"
+
+	// At least one Cassandra cluster is registered with Reaper.
+	ClustersRegistered ReaperConditionType = "ClustersRegistered"
+
+	// The Reaper spec passed validation.
+	ConfigValid ReaperConditionType = "ConfigValid"
+
+	// Every Cassandra pod that should run a Reaper sidecar has a ready one. Only used when
+	// DatacenterAvailability is SIDECAR.
+	SidecarsReady ReaperConditionType = "SidecarsReady"
+)
+
+type ReaperCondition struct {
+	// Type of reaper condition
+	Type ReaperConditionType `json:"type"`
+
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// The .metadata.generation that the condition was set based upon.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// (brief) reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// Human readable message indicating details about last transition.
+	Message string `json:"message,omitempty"`
+}
+
+// The Reaper sidecar of a Cassandra pod.
+type ReaperSidecarStatus struct {
+	// The name of the Cassandra pod.
+	Pod string `json:"pod"`
+
+	// The CassandraDatacenter of the pod.
+	Datacenter string `json:"datacenter,omitempty"`
+
+	Ready bool `json:"ready"`
+}
+
 // ReaperStatus defines the observed state of Reaper
 type ReaperStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -94,6 +499,21 @@ type ReaperStatus struct {
 	Ready bool `json:"ready,omitempty"`
 
 	Clusters []string `json:"clusters,omitempty"`
+
+	// The latest available observations of the Reaper's current state.
+	Conditions []ReaperCondition `json:"conditions,omitempty"`
+
+	// The most recent generation observed by the operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The number of times the schema job has failed since the Reaper spec was last changed.
+	SchemaJobFailures int32 `json:"schemaJobFailures,omitempty"`
+
+	// The replication of the Reaper keyspace as last applied by the schema jobs.
+	Replication *ReplicationConfig `json:"replication,omitempty"`
+
+	// The Reaper sidecars of the Cassandra pods when DatacenterAvailability is SIDECAR.
+	Sidecars []ReaperSidecarStatus `json:"sidecars,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
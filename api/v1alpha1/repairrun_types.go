@@ -0,0 +1,98 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type RepairRunState string
+
+const (
+	RepairRunNotStarted = RepairRunState("NOT_STARTED")
+	RepairRunRunning    = RepairRunState("RUNNING")
+	RepairRunPaused     = RepairRunState("PAUSED")
+	RepairRunDone       = RepairRunState("DONE")
+	RepairRunError      = RepairRunState("ERROR")
+	RepairRunAborted    = RepairRunState("ABORTED")
+	RepairRunDeleted    = RepairRunState("DELETED")
+)
+
+// Returns true if Reaper will not do any more work for a repair run in this state.
+func (s RepairRunState) IsTerminated() bool {
+	return s == RepairRunDone || s == RepairRunError || s == RepairRunAborted || s == RepairRunDeleted
+}
+
+// RepairRunSpec defines the desired state of RepairRun. The repair run is submitted to Reaper
+// once; changes made to the spec afterwards are ignored.
+type RepairRunSpec struct {
+	RepairParameters `json:",inline"`
+}
+
+// RepairRunStatus defines the observed state of RepairRun
+type RepairRunStatus struct {
+	// The id of the repair run in Reaper
+	RunID string `json:"runId,omitempty"`
+
+	// The state of the repair run in Reaper, one of NOT_STARTED, RUNNING, PAUSED, DONE, ERROR,
+	// ABORTED or DELETED
+	State RepairRunState `json:"state,omitempty"`
+
+	SegmentsRepaired int32 `json:"segmentsRepaired,omitempty"`
+
+	TotalSegments int32 `json:"totalSegments,omitempty"`
+
+	CreationTime string `json:"creationTime,omitempty"`
+
+	StartTime string `json:"startTime,omitempty"`
+
+	EndTime string `json:"endTime,omitempty"`
+
+	// The last event reported by Reaper for the repair run
+	LastEvent string `json:"lastEvent,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=repairruns,scope=Namespaced
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`
+// +kubebuilder:printcolumn:name="Keyspace",type=string,JSONPath=`.spec.keyspace`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Repaired",type=integer,JSONPath=`.status.segmentsRepaired`
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.totalSegments`
+
+// RepairRun is the Schema for the repairruns API
+type RepairRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RepairRunSpec   `json:"spec,omitempty"`
+	Status RepairRunStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RepairRunList contains a list of RepairRun
+type RepairRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RepairRun `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RepairRun{}, &RepairRunList{})
+}
@@ -0,0 +1,125 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	DefaultRepairOwner = "reaper-operator"
+)
+
+// Identifies the Reaper instance that manages a repair. If Namespace is empty, the namespace of
+// the referencing object is used.
+type ReaperReference struct {
+	Name string `json:"name"`
+
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Specifies what to repair and how. These are the parameters that Reaper accepts when creating
+// repair schedules and repair runs.
+type RepairParameters struct {
+	// The Reaper instance that will run the repairs. The cluster must be registered with it.
+	Reaper ReaperReference `json:"reaper"`
+
+	// The name of the Cassandra cluster as registered in Reaper.
+	ClusterName string `json:"clusterName"`
+
+	Keyspace string `json:"keyspace"`
+
+	// The tables to repair. All tables in the keyspace are repaired when empty.
+	Tables []string `json:"tables,omitempty"`
+
+	// The owner of the repair in Reaper. Defaults to reaper-operator.
+	Owner string `json:"owner,omitempty"`
+
+	// A value between 0 and 1 that controls how much time Reaper spends sleeping between segments.
+	// Reaper's server default is used when empty.
+	Intensity string `json:"intensity,omitempty"`
+
+	// The value must be either SEQUENTIAL, PARALLEL, or DATACENTER_AWARE. Reaper's server default
+	// is used when empty.
+	RepairParallelism string `json:"repairParallelism,omitempty"`
+
+	// The number of segments to create per node. Reaper's server default is used when not set.
+	SegmentCountPerNode *int32 `json:"segmentCountPerNode,omitempty"`
+
+	IncrementalRepair bool `json:"incrementalRepair,omitempty"`
+}
+
+// RepairScheduleSpec defines the desired state of RepairSchedule
+type RepairScheduleSpec struct {
+	RepairParameters `json:",inline"`
+
+	// The number of days to wait between repairs. Reaper's server default is used when not set.
+	ScheduleDaysBetween *int32 `json:"scheduleDaysBetween,omitempty"`
+
+	// The time of the first repair, e.g., 2020-11-01T02:00:00. The schedule is activated right away
+	// when empty.
+	ScheduleTriggerTime string `json:"scheduleTriggerTime,omitempty"`
+}
+
+// RepairScheduleStatus defines the observed state of RepairSchedule
+type RepairScheduleStatus struct {
+	// The id of the schedule in Reaper
+	ScheduleID string `json:"scheduleId,omitempty"`
+
+	// The state of the schedule in Reaper, e.g., ACTIVE or PAUSED
+	State string `json:"state,omitempty"`
+
+	// The time at which Reaper will next start a repair run for the schedule
+	NextActivation string `json:"nextActivation,omitempty"`
+
+	// The generation of the spec that the schedule in Reaper was created from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// The owner that the schedule in Reaper was created with. Reaper requires it to delete
+	// the schedule.
+	Owner string `json:"owner,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=repairschedules,scope=Namespaced
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`
+// +kubebuilder:printcolumn:name="Keyspace",type=string,JSONPath=`.spec.keyspace`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Next Activation",type=string,JSONPath=`.status.nextActivation`
+
+// RepairSchedule is the Schema for the repairschedules API
+type RepairSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RepairScheduleSpec   `json:"spec,omitempty"`
+	Status RepairScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RepairScheduleList contains a list of RepairSchedule
+type RepairScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RepairSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RepairSchedule{}, &RepairScheduleList{})
+}
@@ -21,12 +21,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthProvider) DeepCopyInto(out *AuthProvider) {
 	*out = *in
+	out.SecretRef = in.SecretRef
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthProvider.
@@ -39,9 +41,34 @@ func (in *AuthProvider) DeepCopy() *AuthProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoScheduling) DeepCopyInto(out *AutoScheduling) {
+	*out = *in
+	if in.ExcludedKeyspaces != nil {
+		in, out := &in.ExcludedKeyspaces, &out.ExcludedKeyspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScheduling.
+func (in *AutoScheduling) DeepCopy() *AutoScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CassandraBackend) DeepCopyInto(out *CassandraBackend) {
 	*out = *in
+	if in.CassandraDatacenterRef != nil {
+		in, out := &in.CassandraDatacenterRef, &out.CassandraDatacenterRef
+		*out = new(CassandraDatacenterReference)
+		**out = **in
+	}
 	in.Replication.DeepCopyInto(&out.Replication)
 	out.AuthProvider = in.AuthProvider
 }
@@ -56,13 +83,28 @@ func (in *CassandraBackend) DeepCopy() *CassandraBackend {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CassandraDatacenterReference) DeepCopyInto(out *CassandraDatacenterReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CassandraDatacenterReference.
+func (in *CassandraDatacenterReference) DeepCopy() *CassandraDatacenterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CassandraDatacenterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Reaper) DeepCopyInto(out *Reaper) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Reaper.
@@ -83,6 +125,22 @@ func (in *Reaper) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReaperCondition) DeepCopyInto(out *ReaperCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperCondition.
+func (in *ReaperCondition) DeepCopy() *ReaperCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ReaperCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReaperList) DeepCopyInto(out *ReaperList) {
 	*out = *in
@@ -115,9 +173,191 @@ func (in *ReaperList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReaperMonitoring) DeepCopyInto(out *ReaperMonitoring) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperMonitoring.
+func (in *ReaperMonitoring) DeepCopy() *ReaperMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(ReaperMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReaperPodTemplate) DeepCopyInto(out *ReaperPodTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperPodTemplate.
+func (in *ReaperPodTemplate) DeepCopy() *ReaperPodTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ReaperPodTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReaperReference) DeepCopyInto(out *ReaperReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperReference.
+func (in *ReaperReference) DeepCopy() *ReaperReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ReaperReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReaperSchemaJob) DeepCopyInto(out *ReaperSchemaJob) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperSchemaJob.
+func (in *ReaperSchemaJob) DeepCopy() *ReaperSchemaJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ReaperSchemaJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReaperService) DeepCopyInto(out *ReaperService) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperService.
+func (in *ReaperService) DeepCopy() *ReaperService {
+	if in == nil {
+		return nil
+	}
+	out := new(ReaperService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReaperSidecarStatus) DeepCopyInto(out *ReaperSidecarStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperSidecarStatus.
+func (in *ReaperSidecarStatus) DeepCopy() *ReaperSidecarStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReaperSidecarStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReaperSpec) DeepCopyInto(out *ReaperSpec) {
 	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
+	in.Service.DeepCopyInto(&out.Service)
+	in.SchemaJob.DeepCopyInto(&out.SchemaJob)
+	if in.UIAuth != nil {
+		in, out := &in.UIAuth, &out.UIAuth
+		*out = new(ReaperUIAuth)
+		**out = **in
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(ReaperMonitoring)
+		(*in).DeepCopyInto(*out)
+	}
 	in.ServerConfig.DeepCopyInto(&out.ServerConfig)
 }
 
@@ -134,6 +374,28 @@ func (in *ReaperSpec) DeepCopy() *ReaperSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReaperStatus) DeepCopyInto(out *ReaperStatus) {
 	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ReaperCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]ReaperSidecarStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperStatus.
@@ -146,6 +408,233 @@ func (in *ReaperStatus) DeepCopy() *ReaperStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReaperUIAuth) DeepCopyInto(out *ReaperUIAuth) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReaperUIAuth.
+func (in *ReaperUIAuth) DeepCopy() *ReaperUIAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ReaperUIAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairParameters) DeepCopyInto(out *RepairParameters) {
+	*out = *in
+	out.Reaper = in.Reaper
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SegmentCountPerNode != nil {
+		in, out := &in.SegmentCountPerNode, &out.SegmentCountPerNode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairParameters.
+func (in *RepairParameters) DeepCopy() *RepairParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairRun) DeepCopyInto(out *RepairRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairRun.
+func (in *RepairRun) DeepCopy() *RepairRun {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepairRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairRunList) DeepCopyInto(out *RepairRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RepairRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairRunList.
+func (in *RepairRunList) DeepCopy() *RepairRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepairRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairRunSpec) DeepCopyInto(out *RepairRunSpec) {
+	*out = *in
+	in.RepairParameters.DeepCopyInto(&out.RepairParameters)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairRunSpec.
+func (in *RepairRunSpec) DeepCopy() *RepairRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairRunStatus) DeepCopyInto(out *RepairRunStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairRunStatus.
+func (in *RepairRunStatus) DeepCopy() *RepairRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairSchedule) DeepCopyInto(out *RepairSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairSchedule.
+func (in *RepairSchedule) DeepCopy() *RepairSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepairSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairScheduleList) DeepCopyInto(out *RepairScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RepairSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairScheduleList.
+func (in *RepairScheduleList) DeepCopy() *RepairScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepairScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairScheduleSpec) DeepCopyInto(out *RepairScheduleSpec) {
+	*out = *in
+	in.RepairParameters.DeepCopyInto(&out.RepairParameters)
+	if in.ScheduleDaysBetween != nil {
+		in, out := &in.ScheduleDaysBetween, &out.ScheduleDaysBetween
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairScheduleSpec.
+func (in *RepairScheduleSpec) DeepCopy() *RepairScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepairScheduleStatus) DeepCopyInto(out *RepairScheduleStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepairScheduleStatus.
+func (in *RepairScheduleStatus) DeepCopy() *RepairScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepairScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicationConfig) DeepCopyInto(out *ReplicationConfig) {
 	*out = *in
@@ -180,11 +669,52 @@ func (in *ReplicationConfig) DeepCopy() *ReplicationConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerConfig) DeepCopyInto(out *ServerConfig) {
 	*out = *in
+	in.AutoScheduling.DeepCopyInto(&out.AutoScheduling)
 	if in.CassandraBackend != nil {
 		in, out := &in.CassandraBackend, &out.CassandraBackend
 		*out = new(CassandraBackend)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		**out = **in
+	}
+	if in.HangingRepairTimeoutMins != nil {
+		in, out := &in.HangingRepairTimeoutMins, &out.HangingRepairTimeoutMins
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RepairRunThreadCount != nil {
+		in, out := &in.RepairRunThreadCount, &out.RepairRunThreadCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScheduleDaysBetween != nil {
+		in, out := &in.ScheduleDaysBetween, &out.ScheduleDaysBetween
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EnableCrossOrigin != nil {
+		in, out := &in.EnableCrossOrigin, &out.EnableCrossOrigin
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableDynamicSeedList != nil {
+		in, out := &in.EnableDynamicSeedList, &out.EnableDynamicSeedList
+		*out = new(bool)
+		**out = **in
+	}
+	if in.JmxConnectionTimeoutInSeconds != nil {
+		in, out := &in.JmxConnectionTimeoutInSeconds, &out.JmxConnectionTimeoutInSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SegmentCountPerNode != nil {
+		in, out := &in.SegmentCountPerNode, &out.SegmentCountPerNode
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerConfig.
@@ -196,3 +726,19 @@ func (in *ServerConfig) DeepCopy() *ServerConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -20,16 +20,29 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/thelastpickle/reaper-operator/pkg/config"
+	"github.com/thelastpickle/reaper-operator/pkg/events"
+	"github.com/thelastpickle/reaper-operator/pkg/metrics"
+	"github.com/thelastpickle/reaper-operator/pkg/reconcile"
 	"github.com/thelastpickle/reaper-operator/pkg/status"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	reapergo "github.com/jsanda/reaper-client-go/reaper"
@@ -39,10 +52,35 @@ import (
 // CassandraDatacenterReconciler reconciles a CassandraDatacenter object
 type CassandraDatacenterReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log                 logr.Logger
+	Scheme              *runtime.Scheme
+	ReaperClientFactory ReaperClientFactory
+	SidecarReconciler   reconcile.SidecarReconciler
+	Recorder            record.EventRecorder
+	// The namespaces of the manager's cache. Reapers in other namespaces cannot be looked up.
+	WatchNamespaces config.WatchNamespaces
 }
 
+const (
+	// The Reaper instance that manages repairs for the cluster. The value is either the name
+	// of a Reaper in the same namespace or name.namespace.
+	ReaperInstanceAnnotation = "reaper.cassandra-reaper.io/instance"
+
+	// Records the Reaper instance that the cluster is registered with so that the cluster can
+	// be unregistered after ReaperInstanceAnnotation is removed or changed.
+	registeredInstanceAnnotation = "reaper.cassandra-reaper.io/registered-instance"
+
+	// Records the seeds that the cluster was last registered with. A cluster that spans
+	// several CassandraDatacenters is registered once with the services of all of them as
+	// seeds, so the seeds change when a data center is added or removed.
+	registeredSeedsAnnotation = "reaper.cassandra-reaper.io/registered-seeds"
+
+	cassdcFinalizer = "reaper.cassandra-reaper.io/finalizer"
+
+	// Indexes CassandraDatacenters by the Reaper instances that their annotations reference.
+	reaperInstanceIndexField = ".metadata.annotations.reaperInstance"
+)
+
 const (
 	DefaultStatusCheckDelay = 30 * time.Minute
 	DefaultShortDelay       = 30 * time.Second
@@ -74,7 +112,8 @@ func getReconcileDelay(name string, defaultDelay time.Duration) time.Duration {
 	}
 }
 
-// +kubebuilder:rbac:groups=cassandra.datastax.com,namespace="reaper-operator",resources=cassandradatacenters,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=cassandra.datastax.com,resources=cassandradatacenters,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *CassandraDatacenterReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
@@ -92,86 +131,424 @@ func (r *CassandraDatacenterReconciler) Reconcile(req ctrl.Request) (ctrl.Result
 
 	cassdc := instance.DeepCopy()
 
-	if reaperName, ok := cassdc.Annotations["reaper.cassandra-reaper.io/instance"]; ok {
-		reaperKey := getReaperKey(reaperName, cassdc.Namespace)
-		reaperInstance := &api.Reaper{}
-
-		err := r.Get(ctx, reaperKey, reaperInstance)
-		if err != nil {
-			if errors.IsNotFound(err) {
-				// It is possible that the Reaper has not been deployed yet or that it has
-				// been deleted, or the annotation could specify an incorrect value.
-				r.Log.Info("reaper instance not found", "reaper", reaperKey)
-				return ctrl.Result{RequeueAfter: longDelay}, nil
-			} else {
-				r.Log.Error(err, "failed to retrieve reaper instance", "reaper", reaperKey)
+	reaperName, annotated := cassdc.Annotations[ReaperInstanceAnnotation]
+	deleted := cassdc.DeletionTimestamp != nil
+
+	// Unregister the cluster if the CassandraDatacenter is deleted or if it is no longer
+	// managed by the Reaper instance that it was registered with. The seeds are only recorded
+	// once the cluster is registered, so there is nothing to unregister without them.
+	if registeredWith, ok := cassdc.Annotations[registeredInstanceAnnotation]; ok {
+		if deleted || !annotated || registeredWith != reaperName {
+			if _, registered := cassdc.Annotations[registeredSeedsAnnotation]; !registered {
+				r.Log.Info("cluster was not registered with reaper, skipping unregistration", "reaper", registeredWith)
+			} else if err = r.unregisterCluster(ctx, cassdc, getReaperKey(registeredWith, cassdc.Namespace), statusManager); err != nil {
+				r.Recorder.Eventf(cassdc, corev1.EventTypeWarning, events.ClusterUnregistrationFailedReason, "failed to unregister cluster %s from reaper %s: %s", cassdc.Spec.ClusterName, registeredWith, err)
+				if cassdc.Annotations[ForceDeleteAnnotation] != "true" {
+					return ctrl.Result{RequeueAfter: shortDelay}, err
+				}
+				r.Log.Info("skipping unregistration of cluster of cassandradatacenter with force delete annotation", "reaper", registeredWith)
+			}
+
+			delete(cassdc.Annotations, registeredInstanceAnnotation)
+			delete(cassdc.Annotations, registeredSeedsAnnotation)
+			if deleted || !annotated {
+				controllerutil.RemoveFinalizer(cassdc, cassdcFinalizer)
+			}
+			if err = r.Update(ctx, cassdc); err != nil {
+				r.Log.Error(err, "failed to update cassandradatacenter after unregistering cluster")
 				return ctrl.Result{RequeueAfter: shortDelay}, err
 			}
+			return ctrl.Result{Requeue: !deleted}, nil
 		}
+	}
 
-		reaper := reaperInstance.DeepCopy()
+	if deleted || !annotated {
+		sidecarRemoved := !deleted && reconcile.RemoveSidecar(cassdc)
+		if controllerutil.ContainsFinalizer(cassdc, cassdcFinalizer) || sidecarRemoved {
+			controllerutil.RemoveFinalizer(cassdc, cassdcFinalizer)
+			if err = r.Update(ctx, cassdc); err != nil {
+				r.Log.Error(err, "failed to remove finalizer and reaper sidecar")
+				return ctrl.Result{RequeueAfter: shortDelay}, err
+			}
+			if sidecarRemoved {
+				r.Recorder.Event(cassdc, corev1.EventTypeNormal, events.SidecarRemovedReason, "removed reaper sidecar")
+			}
+		}
 
-		if !reaper.Status.Ready {
-			r.Log.Info("waiting for reaper to become ready", "reaper", reaperKey)
-			return ctrl.Result{RequeueAfter: shortDelay}, nil
+		if deleted {
+			return ctrl.Result{}, nil
 		}
 
-		// Include the namespace in case Reaper is deployed in a different namespace than
-		// the CassandraDatacenter.
-		reaperSvc := reaper.Name + "-reaper-service" + "." + reaper.Namespace
-		restClient, err := reapergo.NewReaperClient(fmt.Sprintf("http://%s:8080", reaperSvc))
-		if err != nil {
-			r.Log.Error(err, "failed to create reaper rest client", "reaperService", reaperSvc)
+		// The CassandraDatacenter does not have the annotation which means it is not using
+		// Reaper to manage repairs. We requeue the request though to periodically check if
+		// the cluster has been updated to be managed with Reaper.
+		return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil
+	}
+
+	// Record the Reaper instance and add the finalizer before registering the cluster so that
+	// the cluster cannot be left behind in Reaper.
+	if !controllerutil.ContainsFinalizer(cassdc, cassdcFinalizer) || cassdc.Annotations[registeredInstanceAnnotation] != reaperName {
+		controllerutil.AddFinalizer(cassdc, cassdcFinalizer)
+		cassdc.Annotations[registeredInstanceAnnotation] = reaperName
+		if err = r.Update(ctx, cassdc); err != nil {
+			r.Log.Error(err, "failed to add finalizer")
 			return ctrl.Result{RequeueAfter: shortDelay}, err
 		}
+		return ctrl.Result{Requeue: true}, nil
+	}
 
-		_, err = restClient.GetCluster(ctx, cassdc.Spec.ClusterName)
+	reaperKey := getReaperKey(reaperName, cassdc.Namespace)
+	if err = r.WatchNamespaces.Check("reaper", reaperKey.String(), reaperKey.Namespace); err != nil {
+		// The Reaper would not be found in the cache, so the sidecar is left as it is. The
+		// request is retried once the operator is restarted with the namespace.
+		r.Log.Error(err, "cannot look up reaper instance", "reaper", reaperKey)
+		r.Recorder.Event(cassdc, corev1.EventTypeWarning, events.InvalidConfigReason, err.Error())
+		return ctrl.Result{}, nil
+	}
+	reaperInstance := &api.Reaper{}
+
+	err = r.Get(ctx, reaperKey, reaperInstance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// It is possible that the Reaper has not been deployed yet or that it has
+			// been deleted, or the annotation could specify an incorrect value. The request
+			// is not requeued since Reapers are watched.
+			r.Log.Info("reaper instance not found", "reaper", reaperKey)
+			if reconcile.RemoveSidecar(cassdc) {
+				if err = r.Update(ctx, cassdc); err != nil {
+					r.Log.Error(err, "failed to remove reaper sidecar")
+					return ctrl.Result{RequeueAfter: shortDelay}, err
+				}
+				r.Recorder.Eventf(cassdc, corev1.EventTypeNormal, events.SidecarRemovedReason, "removed sidecar of reaper %s which does not exist", reaperKey)
+			}
+			return ctrl.Result{}, nil
+		} else {
+			r.Log.Error(err, "failed to retrieve reaper instance", "reaper", reaperKey)
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+	}
 
-		if err == nil {
-			// The only thing left to do is to make sure that the cluster is listed in
-			// Reaper's status. We still requeue the request to periodically check that
-			// the cluster has not be removed from Reaper.
+	reaper := reaperInstance.DeepCopy()
+
+	if reaper.DeletionTimestamp != nil {
+		// The Reaper unregisters its clusters before it is deleted, so the cluster must not
+		// be registered again. The request is enqueued again once the Reaper is gone.
+		r.Log.Info("reaper instance is being deleted", "reaper", reaperKey)
+		return ctrl.Result{}, nil
+	}
+
+	if result, err := r.reconcileSidecar(ctx, cassdc, reaper, statusManager); result != nil {
+		return *result, err
+	}
+
+	if !reaper.Status.Ready {
+		// The request is enqueued again when the Reaper becomes ready.
+		r.Log.Info("waiting for reaper to become ready", "reaper", reaperKey)
+		return ctrl.Result{}, nil
+	}
+
+	restClient, err := r.ReaperClientFactory(ctx, r.Client, reaper)
+	if err != nil {
+		r.Log.Error(err, "failed to create reaper rest client", "reaper", reaperKey)
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	otherDcs, err := r.getOtherDatacenters(ctx, cassdc, reaperKey)
+	if err != nil {
+		r.Log.Error(err, "failed to list the data centers of the cluster", "cluster", cassdc.Spec.ClusterName)
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+	seeds := getSeeds(append(otherDcs, *cassdc), reaperKey.Namespace)
+
+	_, err = restClient.GetCluster(ctx, cassdc.Spec.ClusterName)
+
+	if err == nil && cassdc.Annotations[registeredSeedsAnnotation] == seeds {
+		// The only thing left to do is to make sure that the cluster is listed in
+		// Reaper's status. We still requeue the request to periodically check that
+		// the cluster has not be removed from Reaper.
+		if err = statusManager.AddClusterToStatus(ctx, reaper, cassdc); err == nil {
+			return ctrl.Result{RequeueAfter: statusCheckDelay}, nil
+		} else {
+			r.Log.Error(err, "failed to re-add cluster in reaper status", "reaper", reaperKey)
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+	}
+
+	// Registering a cluster that already exists updates its seeds.
+	if err == nil || err == reapergo.CassandraClusterNotFound {
+		r.Log.Info("registering cluster with reaper", "reaper", reaperKey, "seeds", seeds)
+		if err = restClient.AddCluster(ctx, cassdc.Spec.ClusterName, seeds); err == nil {
+			message := fmt.Sprintf("registered cluster %s with reaper %s using seeds %s", cassdc.Spec.ClusterName, reaperKey, seeds)
+			r.Recorder.Event(cassdc, corev1.EventTypeNormal, events.ClusterRegisteredReason, message)
+			r.Recorder.Event(reaper, corev1.EventTypeNormal, events.ClusterRegisteredReason, message)
+			cassdc.Annotations[registeredSeedsAnnotation] = seeds
+			if err = r.Update(ctx, cassdc); err != nil {
+				r.Log.Error(err, "failed to record the registered seeds", "reaper", reaperKey)
+				return ctrl.Result{RequeueAfter: shortDelay}, err
+			}
 			if err = statusManager.AddClusterToStatus(ctx, reaper, cassdc); err == nil {
 				return ctrl.Result{RequeueAfter: statusCheckDelay}, nil
 			} else {
-				r.Log.Error(err, "failed to re-add cluster in reaper status", "reaper", reaperKey)
+				r.Log.Error(err, "failed to add cluster in reaper status", "reaper", reaperKey)
 				return ctrl.Result{RequeueAfter: shortDelay}, err
 			}
+		} else {
+			r.Log.Error(err, "failed to register cluster with reaper", "reaper", reaperKey)
+			message := fmt.Sprintf("failed to register cluster %s with reaper %s: %s", cassdc.Spec.ClusterName, reaperKey, err)
+			r.Recorder.Event(cassdc, corev1.EventTypeWarning, events.ClusterRegistrationFailedReason, message)
+			r.Recorder.Event(reaper, corev1.EventTypeWarning, events.ClusterRegistrationFailedReason, message)
+			metrics.IncClusterRegistrationFailures(metrics.ClusterKey{Namespace: reaper.Namespace, Reaper: reaper.Name, Cluster: cassdc.Spec.ClusterName})
+			return ctrl.Result{RequeueAfter: shortDelay}, err
 		}
+	}
 
-		if err == reapergo.CassandraClusterNotFound {
-			r.Log.Info("registering cluster with reaper", "reaper", reaperKey)
-			if err = restClient.AddCluster(ctx, cassdc.Spec.ClusterName, cassdc.GetDatacenterServiceName()); err == nil {
-				if err = statusManager.AddClusterToStatus(ctx, reaper, cassdc); err == nil {
-					return ctrl.Result{RequeueAfter: statusCheckDelay}, nil
-				} else {
-					r.Log.Error(err, "failed to add cluster in reaper status", "reaper", reaperKey)
-					return ctrl.Result{RequeueAfter: shortDelay}, err
-				}
-			} else {
-				r.Log.Error(err, "failed to register cluster with reaper", "reaper", reaperKey)
-				return ctrl.Result{RequeueAfter: shortDelay}, err
-			}
+	r.Log.Error(err, "failed to get cluster from reaper", "reaper", reaperKey)
+	r.Recorder.Eventf(cassdc, corev1.EventTypeWarning, events.ClusterRegistrationFailedReason, "failed to get cluster %s from reaper %s: %s", cassdc.Spec.ClusterName, reaperKey, err)
+	return ctrl.Result{RequeueAfter: shortDelay}, err
+}
+
+// Deletes the cluster from the Reaper instance and removes it from the instance's status. If
+// other data centers of the cluster are still managed by the Reaper instance, the cluster is
+// registered again with their seeds instead. There is nothing to do if the Reaper instance no
+// longer exists or is being deleted. An error is returned if Reaper is not ready so that the cleanup is retried.
+func (r *CassandraDatacenterReconciler) unregisterCluster(ctx context.Context, cassdc *cassdcv1beta1.CassandraDatacenter, reaperKey types.NamespacedName, statusManager *status.StatusManager) error {
+	if err := r.WatchNamespaces.Check("reaper", reaperKey.String(), reaperKey.Namespace); err != nil {
+		// Retrying cannot succeed, so the cluster is left registered rather than blocking the
+		// deletion of the CassandraDatacenter.
+		r.Log.Error(err, "cannot look up reaper instance, skipping unregistration of cluster", "reaper", reaperKey)
+		r.Recorder.Eventf(cassdc, corev1.EventTypeWarning, events.ClusterUnregistrationFailedReason, "skipped unregistration of cluster %s: %s", cassdc.Spec.ClusterName, err)
+		return nil
+	}
+
+	reaper := &api.Reaper{}
+	if err := r.Get(ctx, reaperKey, reaper); err != nil {
+		if errors.IsNotFound(err) {
+			r.Log.Info("reaper instance not found, skipping unregistration of cluster", "reaper", reaperKey)
+			return nil
+		}
+		r.Log.Error(err, "failed to retrieve reaper instance", "reaper", reaperKey)
+		return err
+	}
+
+	if reaper.DeletionTimestamp != nil {
+		r.Log.Info("reaper instance is being deleted and unregisters its clusters, skipping unregistration of cluster", "reaper", reaperKey)
+		return nil
+	}
+
+	if !reaper.Status.Ready {
+		r.Log.Info("waiting for reaper to become ready to unregister cluster", "reaper", reaperKey)
+		return fmt.Errorf("reaper %s is not ready", reaperKey)
+	}
+
+	restClient, err := r.ReaperClientFactory(ctx, r.Client, reaper)
+	if err != nil {
+		r.Log.Error(err, "failed to create reaper rest client", "reaper", reaperKey)
+		return err
+	}
+
+	otherDcs, err := r.getOtherDatacenters(ctx, cassdc, reaperKey)
+	if err != nil {
+		r.Log.Error(err, "failed to list the data centers of the cluster", "cluster", cassdc.Spec.ClusterName)
+		return err
+	}
+
+	if len(otherDcs) > 0 {
+		seeds := getSeeds(otherDcs, reaperKey.Namespace)
+		r.Log.Info("removing data center from cluster seeds", "reaper", reaperKey, "cluster", cassdc.Spec.ClusterName, "seeds", seeds)
+		if err = restClient.AddCluster(ctx, cassdc.Spec.ClusterName, seeds); err != nil {
+			r.Log.Error(err, "failed to update cluster seeds in reaper", "reaper", reaperKey)
+			r.Recorder.Eventf(reaper, corev1.EventTypeWarning, events.ClusterRegistrationFailedReason, "failed to update seeds of cluster %s: %s", cassdc.Spec.ClusterName, err)
+			metrics.IncClusterRegistrationFailures(metrics.ClusterKey{Namespace: reaper.Namespace, Reaper: reaper.Name, Cluster: cassdc.Spec.ClusterName})
+			return err
 		}
+		message := fmt.Sprintf("removed data center %s from the seeds of cluster %s", cassdc.Name, cassdc.Spec.ClusterName)
+		r.Recorder.Event(cassdc, corev1.EventTypeNormal, events.ClusterRegisteredReason, message)
+		r.Recorder.Event(reaper, corev1.EventTypeNormal, events.ClusterRegisteredReason, message)
+		return nil
+	}
+
+	r.Log.Info("unregistering cluster from reaper", "reaper", reaperKey, "cluster", cassdc.Spec.ClusterName)
+	if err = restClient.DeleteCluster(ctx, cassdc.Spec.ClusterName); err != nil && err != reapergo.CassandraClusterNotFound {
+		r.Log.Error(err, "failed to unregister cluster from reaper", "reaper", reaperKey)
+		r.Recorder.Eventf(reaper, corev1.EventTypeWarning, events.ClusterUnregistrationFailedReason, "failed to unregister cluster %s: %s", cassdc.Spec.ClusterName, err)
+		return err
 	}
+	message := fmt.Sprintf("unregistered cluster %s from reaper %s", cassdc.Spec.ClusterName, reaperKey)
+	r.Recorder.Event(cassdc, corev1.EventTypeNormal, events.ClusterUnregisteredReason, message)
+	r.Recorder.Event(reaper, corev1.EventTypeNormal, events.ClusterUnregisteredReason, message)
 
-	// The CassandraDatacenter does not have the annotation which means it is not using
-	// Reaper to manage repairs. We requeue the request though to periodically check if
-	// the cluster has been updated to be managed with Reaper.
-	return ctrl.Result{RequeueAfter: 10 * time.Minute}, nil
+	if err = statusManager.RemoveClusterFromStatus(ctx, reaper, cassdc); err != nil {
+		r.Log.Error(err, "failed to remove cluster from reaper status", "reaper", reaperKey)
+		return err
+	}
+
+	return nil
 }
 
-func getReaperKey(instanceName, cassdcNamespace string) types.NamespacedName {
-	parts := strings.Split(instanceName, ".")
-	if len(parts) == 1 {
-		return types.NamespacedName{Namespace: cassdcNamespace, Name: instanceName}
+// Injects the Reaper sidecar into the pods of the CassandraDatacenter when the Reaper runs in
+// SIDECAR mode and removes it otherwise. The sidecar is injected once the schema is initialized
+// since Reaper cannot start without it. A non-nil result is returned if the CassandraDatacenter
+// was updated or the sidecar cannot be injected yet.
+func (r *CassandraDatacenterReconciler) reconcileSidecar(ctx context.Context, cassdc *cassdcv1beta1.CassandraDatacenter, reaper *api.Reaper, statusManager *status.StatusManager) (*ctrl.Result, error) {
+	reaperKey := types.NamespacedName{Namespace: reaper.Namespace, Name: reaper.Name}
+
+	var updated bool
+	if reaper.Spec.ServerConfig.DatacenterAvailability != api.DatacenterAvailabilitySidecar {
+		updated = reconcile.RemoveSidecar(cassdc)
 	} else {
-		return types.NamespacedName{Namespace: parts[1], Name: parts[0]}
+		// The sidecar references the Reaper's secrets which have to be in the namespace of
+		// the pods.
+		if reaper.Namespace != cassdc.Namespace {
+			err := fmt.Errorf("reaper %s is not in the namespace of the cassandradatacenter", reaperKey)
+			r.Log.Error(err, "cannot inject reaper sidecar")
+			r.Recorder.Eventf(cassdc, corev1.EventTypeWarning, events.InvalidConfigReason, "cannot inject reaper sidecar: %s", err)
+			return &ctrl.Result{}, nil
+		}
+
+		if !status.IsConditionTrue(&reaper.Status, api.SchemaInitialized) {
+			r.Log.Info("waiting for reaper schema to be initialized before injecting sidecar", "reaper", reaperKey)
+			return &ctrl.Result{RequeueAfter: shortDelay}, nil
+		}
+
+		if err := deriveCassandraBackend(ctx, r.Client, r.WatchNamespaces, reaper); err != nil {
+			r.Log.Error(err, "failed to derive cassandra backend", "reaper", reaperKey)
+			return &ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+
+		req := reconcile.ReaperRequest{Reaper: reaper, Logger: r.Log.WithValues("reaper", reaperKey), StatusManager: statusManager}
+		var err error
+		if updated, err = r.SidecarReconciler.ReconcileSidecar(ctx, req, cassdc); err != nil {
+			r.Log.Error(err, "failed to reconcile reaper sidecar", "reaper", reaperKey)
+			return &ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+	}
+
+	if !updated {
+		return nil, nil
+	}
+
+	r.Log.Info("updating reaper sidecar", "reaper", reaperKey)
+	if err := r.Update(ctx, cassdc); err != nil {
+		r.Log.Error(err, "failed to update reaper sidecar", "reaper", reaperKey)
+		return &ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+	if reaper.Spec.ServerConfig.DatacenterAvailability == api.DatacenterAvailabilitySidecar {
+		r.Recorder.Eventf(cassdc, corev1.EventTypeNormal, events.SidecarInjectedReason, "injected sidecar of reaper %s", reaperKey)
+	} else {
+		r.Recorder.Eventf(cassdc, corev1.EventTypeNormal, events.SidecarRemovedReason, "removed sidecar of reaper %s which does not run in SIDECAR mode", reaperKey)
 	}
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+// Returns the other CassandraDatacenters of the cluster that are managed by the Reaper instance
+// and are not being deleted. The data centers can be in any of the watched namespaces.
+func (r *CassandraDatacenterReconciler) getOtherDatacenters(ctx context.Context, cassdc *cassdcv1beta1.CassandraDatacenter, reaperKey types.NamespacedName) ([]cassdcv1beta1.CassandraDatacenter, error) {
+	// The cache only holds the watched namespaces, so listing without a namespace lists all of
+	// them.
+	cassdcs := &cassdcv1beta1.CassandraDatacenterList{}
+	if err := r.List(ctx, cassdcs); err != nil {
+		return nil, err
+	}
+
+	otherDcs := make([]cassdcv1beta1.CassandraDatacenter, 0)
+	for _, other := range cassdcs.Items {
+		if (other.Namespace == cassdc.Namespace && other.Name == cassdc.Name) || other.Spec.ClusterName != cassdc.Spec.ClusterName || other.DeletionTimestamp != nil {
+			continue
+		}
+		if reaperName, ok := other.Annotations[ReaperInstanceAnnotation]; ok && getReaperKey(reaperName, other.Namespace) == reaperKey {
+			otherDcs = append(otherDcs, other)
+		}
+	}
+
+	return otherDcs, nil
+}
+
+// Returns the sorted, comma-separated services of the data centers with which the cluster is
+// registered. Services outside of the Reaper's namespace are qualified with their namespace so
+// that Reaper can resolve them.
+func getSeeds(cassdcs []cassdcv1beta1.CassandraDatacenter, reaperNamespace string) string {
+	seeds := make([]string, 0, len(cassdcs))
+	for _, cassdc := range cassdcs {
+		seed := cassdc.GetDatacenterServiceName()
+		if cassdc.Namespace != reaperNamespace {
+			seed += "." + cassdc.Namespace
+		}
+		seeds = append(seeds, seed)
+	}
+	sort.Strings(seeds)
+
+	return strings.Join(seeds, ",")
+}
+
+func getReaperKey(instanceName, cassdcNamespace string) types.NamespacedName {
+	// Namespaces cannot contain dots while names can, so the namespace follows the last dot.
+	if i := strings.LastIndex(instanceName, "."); i >= 0 {
+		return types.NamespacedName{Namespace: instanceName[i+1:], Name: instanceName[:i]}
+	}
+	return types.NamespacedName{Namespace: cassdcNamespace, Name: instanceName}
+}
+
+// Returns the keys of the Reaper instances that the CassandraDatacenter is or should be
+// registered with.
+func getReaperInstanceKeys(cassdc *cassdcv1beta1.CassandraDatacenter) []string {
+	keys := make([]string, 0, 2)
+	for _, annotation := range []string{ReaperInstanceAnnotation, registeredInstanceAnnotation} {
+		if instance, found := cassdc.Annotations[annotation]; found {
+			key := getReaperKey(instance, cassdc.Namespace).String()
+			if len(keys) == 0 || keys[0] != key {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// Returns the requests for the CassandraDatacenters that reference the Reaper.
+func (r *CassandraDatacenterReconciler) mapReaperToDatacenters(obj handler.MapObject) []ctrl.Request {
+	reaperKey := types.NamespacedName{Namespace: obj.Meta.GetNamespace(), Name: obj.Meta.GetName()}
+
+	cassdcs := &cassdcv1beta1.CassandraDatacenterList{}
+	if err := r.List(context.Background(), cassdcs, client.MatchingFields{reaperInstanceIndexField: reaperKey.String()}); err != nil {
+		r.Log.Error(err, "failed to list cassandradatacenters that reference reaper", "reaper", reaperKey)
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(cassdcs.Items))
+	for _, cassdc := range cassdcs.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: cassdc.Namespace, Name: cassdc.Name}})
+	}
+	return requests
+}
+
+// Only changes to the readiness, the spec or the deletion of a Reaper affect the
+// CassandraDatacenters that reference it. A Reaper that loses its state when it restarts
+// becomes ready again, which makes the clusters get registered again.
+var reaperChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldReaper, ok := e.ObjectOld.(*api.Reaper)
+		if !ok {
+			return true
+		}
+		newReaper, ok := e.ObjectNew.(*api.Reaper)
+		if !ok {
+			return true
+		}
+		return oldReaper.Status.Ready != newReaper.Status.Ready || oldReaper.Generation != newReaper.Generation ||
+			(oldReaper.DeletionTimestamp == nil) != (newReaper.DeletionTimestamp == nil)
+	},
 }
 
 func (r *CassandraDatacenterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &cassdcv1beta1.CassandraDatacenter{}, reaperInstanceIndexField, func(obj runtime.Object) []string {
+		return getReaperInstanceKeys(obj.(*cassdcv1beta1.CassandraDatacenter))
+	})
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&cassdcv1beta1.CassandraDatacenter{}).
+		Watches(&source.Kind{Type: &api.Reaper{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.mapReaperToDatacenters)},
+			builder.WithPredicates(reaperChangedPredicate)).
 		Complete(r)
 }
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Returns a CassandraDatacenter that is being deleted and that was registered with a Reaper
+// that is not ready.
+func newDeletedDatacenter(annotations map[string]string) *cassdcv1beta1.CassandraDatacenter {
+	now := metav1.Now()
+	return &cassdcv1beta1.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         repairTestNamespace,
+			Name:              "dc1",
+			Annotations:       annotations,
+			Finalizers:        []string{cassdcFinalizer},
+			DeletionTimestamp: &now,
+		},
+		Spec: cassdcv1beta1.CassandraDatacenterSpec{ClusterName: "test"},
+	}
+}
+
+func reconcileDeletedDatacenter(t *testing.T, cassdc *cassdcv1beta1.CassandraDatacenter) (*cassdcv1beta1.CassandraDatacenter, error) {
+	reaper := &api.Reaper{ObjectMeta: metav1.ObjectMeta{Namespace: repairTestNamespace, Name: "reaper"}}
+	r := &CassandraDatacenterReconciler{
+		Client:              fake.NewFakeClientWithScheme(newTestScheme(), []runtime.Object{reaper, cassdc}...),
+		Log:                 ctrl.Log.WithName("controllers").WithName("CassandraDatacenter"),
+		ReaperClientFactory: newFakeReaperClient().factory,
+		Recorder:            record.NewFakeRecorder(10),
+	}
+	key := types.NamespacedName{Namespace: cassdc.Namespace, Name: cassdc.Name}
+
+	_, err := r.Reconcile(ctrl.Request{NamespacedName: key})
+
+	updated := &cassdcv1beta1.CassandraDatacenter{}
+	if getErr := r.Get(context.Background(), key, updated); getErr != nil {
+		t.Fatalf("failed to get cassandradatacenter: %s", getErr)
+	}
+	return updated, err
+}
+
+func TestCassandraDatacenterDeleteNotRegistered(t *testing.T) {
+	// The registration never succeeded, so the seeds were not recorded.
+	cassdc, err := reconcileDeletedDatacenter(t, newDeletedDatacenter(map[string]string{
+		ReaperInstanceAnnotation:     "reaper",
+		registeredInstanceAnnotation: "reaper",
+	}))
+	if err != nil {
+		t.Fatalf("failed to reconcile cassandradatacenter: %s", err)
+	}
+	if len(cassdc.Finalizers) != 0 {
+		t.Errorf("expected finalizer to be removed, got (%v)", cassdc.Finalizers)
+	}
+}
+
+func TestCassandraDatacenterForceDelete(t *testing.T) {
+	annotations := map[string]string{
+		ReaperInstanceAnnotation:     "reaper",
+		registeredInstanceAnnotation: "reaper",
+		registeredSeedsAnnotation:    "dc1-service",
+	}
+
+	cassdc, err := reconcileDeletedDatacenter(t, newDeletedDatacenter(annotations))
+	if err == nil {
+		t.Errorf("expected unregistration from reaper that is not ready to fail")
+	}
+	if len(cassdc.Finalizers) != 1 {
+		t.Errorf("expected finalizer to be kept, got (%v)", cassdc.Finalizers)
+	}
+
+	annotations[ForceDeleteAnnotation] = "true"
+	cassdc, err = reconcileDeletedDatacenter(t, newDeletedDatacenter(annotations))
+	if err != nil {
+		t.Fatalf("failed to reconcile cassandradatacenter: %s", err)
+	}
+	if len(cassdc.Finalizers) != 0 {
+		t.Errorf("expected finalizer to be removed, got (%v)", cassdc.Finalizers)
+	}
+}
+
+func newClusterDatacenter(namespace, name, reaperInstance string) *cassdcv1beta1.CassandraDatacenter {
+	return &cassdcv1beta1.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Annotations: map[string]string{ReaperInstanceAnnotation: reaperInstance},
+		},
+		Spec: cassdcv1beta1.CassandraDatacenterSpec{ClusterName: "test"},
+	}
+}
+
+func TestGetOtherDatacenters(t *testing.T) {
+	dc1 := newClusterDatacenter(repairTestNamespace, "dc1", "reaper")
+	// The other data centers of the cluster are in different namespaces.
+	dc2 := newClusterDatacenter("other", "dc2", "reaper."+repairTestNamespace)
+	dc1Other := newClusterDatacenter("other", "dc1", "reaper."+repairTestNamespace)
+	unmanaged := newClusterDatacenter("other", "dc3", "other-reaper")
+
+	r := &CassandraDatacenterReconciler{
+		Client: fake.NewFakeClientWithScheme(newTestScheme(), dc1, dc2, dc1Other, unmanaged),
+	}
+	reaperKey := types.NamespacedName{Namespace: repairTestNamespace, Name: "reaper"}
+
+	otherDcs, err := r.getOtherDatacenters(context.Background(), dc1, reaperKey)
+	if err != nil {
+		t.Fatalf("failed to get other data centers: %s", err)
+	}
+	if len(otherDcs) != 2 {
+		t.Fatalf("expected data centers (other/dc1, other/dc2), got (%v)", otherDcs)
+	}
+
+	expected := "test-dc1-service,test-dc1-service.other,test-dc2-service.other"
+	if seeds := getSeeds(append(otherDcs, *dc1), reaperKey.Namespace); seeds != expected {
+		t.Errorf("expected seeds (%s), got (%s)", expected, seeds)
+	}
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"github.com/thelastpickle/reaper-operator/pkg/reaperclient"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeReaperClient keeps repair schedules and runs in memory and rejects the requests that
+// Reaper rejects. The cluster endpoints are not implemented.
+type fakeReaperClient struct {
+	reaperclient.Client
+
+	schedules map[string]*reaperclient.RepairSchedule
+
+	runs map[string]*reaperclient.RepairRun
+
+	nextID int
+}
+
+func newFakeReaperClient() *fakeReaperClient {
+	return &fakeReaperClient{
+		schedules: make(map[string]*reaperclient.RepairSchedule),
+		runs:      make(map[string]*reaperclient.RepairRun),
+	}
+}
+
+func (f *fakeReaperClient) factory(ctx context.Context, c client.Client, reaper *api.Reaper) (reaperclient.Client, error) {
+	return f, nil
+}
+
+func (f *fakeReaperClient) newID() string {
+	f.nextID++
+	return strconv.Itoa(f.nextID)
+}
+
+func (f *fakeReaperClient) CreateRepairSchedule(ctx context.Context, options reaperclient.RepairScheduleOptions) (*reaperclient.RepairSchedule, error) {
+	schedule := &reaperclient.RepairSchedule{
+		Id:           f.newID(),
+		Owner:        options.Owner,
+		State:        "ACTIVE",
+		ClusterName:  options.ClusterName,
+		KeyspaceName: options.Keyspace,
+	}
+	f.schedules[schedule.Id] = schedule
+	copied := *schedule
+	return &copied, nil
+}
+
+func (f *fakeReaperClient) GetRepairSchedule(ctx context.Context, id string) (*reaperclient.RepairSchedule, error) {
+	schedule, found := f.schedules[id]
+	if !found {
+		return nil, reaperclient.RepairScheduleNotFound
+	}
+	copied := *schedule
+	return &copied, nil
+}
+
+func (f *fakeReaperClient) PauseRepairSchedule(ctx context.Context, id string) error {
+	schedule, found := f.schedules[id]
+	if !found {
+		return reaperclient.RepairScheduleNotFound
+	}
+	schedule.State = "PAUSED"
+	return nil
+}
+
+func (f *fakeReaperClient) DeleteRepairSchedule(ctx context.Context, id, owner string) error {
+	schedule, found := f.schedules[id]
+	if !found {
+		return reaperclient.RepairScheduleNotFound
+	}
+	if schedule.State == "ACTIVE" {
+		return fmt.Errorf("failed to delete repair schedule (%s): status code (409)", id)
+	}
+	if schedule.Owner != owner {
+		return fmt.Errorf("failed to delete repair schedule (%s): status code (409)", id)
+	}
+	delete(f.schedules, id)
+	return nil
+}
+
+func (f *fakeReaperClient) GetRepairRun(ctx context.Context, id string) (*reaperclient.RepairRun, error) {
+	run, found := f.runs[id]
+	if !found {
+		return nil, reaperclient.RepairRunNotFound
+	}
+	copied := *run
+	return &copied, nil
+}
+
+func (f *fakeReaperClient) UpdateRepairRunState(ctx context.Context, id, state string) (*reaperclient.RepairRun, error) {
+	run, found := f.runs[id]
+	if !found {
+		return nil, reaperclient.RepairRunNotFound
+	}
+	if api.RepairRunState(run.State).IsTerminated() {
+		return nil, fmt.Errorf("failed to set state of repair run (%s) to %s: status code (409)", id, state)
+	}
+	run.State = state
+	copied := *run
+	return &copied, nil
+}
+
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = api.AddToScheme(scheme)
+	_ = cassdcv1beta1.AddToScheme(scheme)
+	return scheme
+}
@@ -18,19 +18,45 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	"github.com/go-logr/logr"
+	reapergo "github.com/jsanda/reaper-client-go/reaper"
 	"github.com/thelastpickle/reaper-operator/pkg/config"
+	"github.com/thelastpickle/reaper-operator/pkg/events"
+	"github.com/thelastpickle/reaper-operator/pkg/metrics"
 	"github.com/thelastpickle/reaper-operator/pkg/status"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
 	"github.com/thelastpickle/reaper-operator/pkg/reconcile"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// Indexes Reapers by the names of the secrets that they reference.
+	secretRefIndexField = ".spec.secretRefs"
+
+	// Blocks the deletion of a Reaper until its clusters are unregistered and, with the Delete
+	// deletion policy, its keyspace is dropped.
+	reaperFinalizer = "reaper.cassandra-reaper.io/finalizer"
+
+	// Setting this annotation to true on a Reaper or a CassandraDatacenter lets it be deleted
+	// even if the cleanup fails. The failures are still reported as events.
+	ForceDeleteAnnotation = "reaper.cassandra-reaper.io/force-delete"
 )
 
 // ReaperReconciler reconciles a Reaper object
@@ -41,15 +67,24 @@ type ReaperReconciler struct {
 	ServiceReconciler    reconcile.ServiceReconciler
 	DeploymentReconciler reconcile.DeploymentReconciler
 	SchemaReconciler     reconcile.SchemaReconciler
+	MonitoringReconciler reconcile.MonitoringReconciler
 	Validator            config.Validator
+	ReaperClientFactory  ReaperClientFactory
+	Recorder             record.EventRecorder
+	// The namespaces of the manager's cache. CassandraDatacenters in other namespaces cannot
+	// be looked up.
+	WatchNamespaces config.WatchNamespaces
 }
 
-// +kubebuilder:rbac:groups=reaper.cassandra-reaper.io,namespace="reaper-operator",resources=reapers,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=reaper.cassandra-reaper.io,namespace="reaper-operator",resources=reapers/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups="apps",namespace="reaper-operator",resources=deployments,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups="batch",namespace="reaper-operator",resources=jobs,verbs=get;list;watch;create;delete
-// +kubebuilder:rbac:groups="",namespace="reaper-operator",resources=services,verbs=get;list;watch;create
-// +kubebuilder:rbac:groups="",namespace="reaper-operator",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=reaper.cassandra-reaper.io,resources=reapers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=reaper.cassandra-reaper.io,resources=reapers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
 
 func (r *ReaperReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
@@ -63,6 +98,7 @@ func (r *ReaperReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	err := r.Get(ctx, req.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			metrics.DeleteReaperStatus(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
@@ -70,7 +106,27 @@ func (r *ReaperReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	instance = instance.DeepCopy()
 
+	// Status changes trigger reconciles, so the metrics follow the status.
+	metrics.SetReaperStatus(instance)
+
+	if instance.DeletionTimestamp != nil {
+		return r.finalizeReaper(ctx, instance, reqLogger, statusManager)
+	}
+
+	if !controllerutil.ContainsFinalizer(instance, reaperFinalizer) {
+		controllerutil.AddFinalizer(instance, reaperFinalizer)
+		if err = r.Update(ctx, instance); err != nil {
+			reqLogger.Error(err, "failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	if err := r.Validator.Validate(instance); err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, events.InvalidConfigReason, err.Error())
+		if statusErr := statusManager.SetCondition(ctx, instance, api.ConfigValid, corev1.ConditionFalse, status.ValidationFailedReason, err.Error()); statusErr != nil {
+			reqLogger.Error(statusErr, "failed to update status")
+		}
 		return ctrl.Result{}, err
 	}
 
@@ -81,12 +137,30 @@ func (r *ReaperReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	if err = deriveCassandraBackend(ctx, r.Client, r.WatchNamespaces, instance); err != nil {
+		reqLogger.Error(err, "failed to derive cassandra backend")
+		r.Recorder.Event(instance, corev1.EventTypeWarning, events.BackendUnavailableReason, err.Error())
+		if statusErr := statusManager.SetCondition(ctx, instance, api.ConfigValid, corev1.ConditionFalse, status.DatacenterUnavailableReason, err.Error()); statusErr != nil {
+			reqLogger.Error(statusErr, "failed to update status")
+		}
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+
+	if err = statusManager.SetCondition(ctx, instance, api.ConfigValid, corev1.ConditionTrue, status.ValidationSucceededReason, ""); err != nil {
+		reqLogger.Error(err, "failed to update status")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+
 	reaperReq := reconcile.ReaperRequest{Reaper: instance, Logger: reqLogger, StatusManager: statusManager}
 
 	if result, err := r.ServiceReconciler.ReconcileService(ctx, reaperReq); result != nil {
 		return *result, err
 	}
 
+	if result, err := r.MonitoringReconciler.ReconcileMonitoring(ctx, reaperReq); result != nil {
+		return *result, err
+	}
+
 	if result, err := r.SchemaReconciler.ReconcileSchema(ctx, reaperReq); result != nil {
 		return *result, err
 	}
@@ -100,9 +174,152 @@ func (r *ReaperReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	return ctrl.Result{}, nil
 }
 
+// Unregisters the clusters of the Reaper and drops its keyspace according to the deletion
+// policy before removing the finalizer. Failures are reported as events and the cleanup is
+// retried unless the Reaper has the force delete annotation.
+func (r *ReaperReconciler) finalizeReaper(ctx context.Context, reaper *api.Reaper, reqLogger logr.Logger, statusManager *status.StatusManager) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(reaper, reaperFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	force := reaper.Annotations[ForceDeleteAnnotation] == "true"
+
+	if err := r.unregisterClusters(ctx, reaper, reqLogger, statusManager); err != nil {
+		r.Recorder.Event(reaper, corev1.EventTypeWarning, events.ClusterUnregistrationFailedReason, err.Error())
+		if !force {
+			return ctrl.Result{}, err
+		}
+	}
+
+	err := deriveCassandraBackend(ctx, r.Client, r.WatchNamespaces, reaper)
+	if err == nil {
+		reaperReq := reconcile.ReaperRequest{Reaper: reaper, Logger: reqLogger, StatusManager: statusManager}
+		var result *ctrl.Result
+		if result, err = r.SchemaReconciler.DeleteSchema(ctx, reaperReq); err == nil && result != nil {
+			return *result, nil
+		}
+	}
+	if err != nil {
+		r.Recorder.Event(reaper, corev1.EventTypeWarning, events.KeyspaceDropFailedReason, err.Error())
+		if !force {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if force {
+		reqLogger.Info("removing finalizer of reaper with force delete annotation")
+	}
+
+	controllerutil.RemoveFinalizer(reaper, reaperFinalizer)
+	if err = r.Update(ctx, reaper); err != nil {
+		reqLogger.Error(err, "failed to remove finalizer")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// Deletes the clusters in .status.clusters from Reaper along with their repair schedules and
+// runs. The clusters that are unregistered are removed from the status so that they are not
+// unregistered again when the cleanup is retried. There is nothing to unregister with the
+// memory backend since Reaper's state goes away with its pods.
+func (r *ReaperReconciler) unregisterClusters(ctx context.Context, reaper *api.Reaper, reqLogger logr.Logger, statusManager *status.StatusManager) error {
+	if len(reaper.Status.Clusters) == 0 || reaper.Spec.ServerConfig.StorageType == api.StorageTypeMemory {
+		return nil
+	}
+
+	if !reaper.Status.Ready {
+		return fmt.Errorf("reaper is not ready to unregister clusters %s", strings.Join(reaper.Status.Clusters, ", "))
+	}
+
+	restClient, err := r.ReaperClientFactory(ctx, r.Client, reaper)
+	if err != nil {
+		return fmt.Errorf("failed to create reaper rest client: %w", err)
+	}
+
+	remaining := make([]string, 0)
+	var errs []string
+	for _, cluster := range reaper.Status.Clusters {
+		reqLogger.Info("unregistering cluster from reaper", "cluster", cluster)
+		if err := restClient.DeleteCluster(ctx, cluster); err != nil && err != reapergo.CassandraClusterNotFound {
+			reqLogger.Error(err, "failed to unregister cluster from reaper", "cluster", cluster)
+			remaining = append(remaining, cluster)
+			errs = append(errs, err.Error())
+		} else {
+			r.Recorder.Eventf(reaper, corev1.EventTypeNormal, events.ClusterUnregisteredReason, "unregistered cluster %s", cluster)
+		}
+	}
+
+	if err = statusManager.SetClusters(ctx, reaper, remaining); err != nil {
+		reqLogger.Error(err, "failed to update status")
+		return err
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to unregister clusters %s: %s", strings.Join(remaining, ", "), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Fills in the Cassandra backend settings that are derived from the referenced
+// CassandraDatacenter. The derived settings are not stored in the spec so that they follow
+// changes to the data centers.
+func deriveCassandraBackend(ctx context.Context, c client.Client, namespaces config.WatchNamespaces, reaper *api.Reaper) error {
+	backend := reaper.Spec.ServerConfig.CassandraBackend
+	if backend == nil || backend.CassandraDatacenterRef == nil {
+		return nil
+	}
+
+	key := types.NamespacedName{Namespace: backend.CassandraDatacenterRef.Namespace, Name: backend.CassandraDatacenterRef.Name}
+	if len(key.Namespace) == 0 {
+		key.Namespace = reaper.Namespace
+	}
+	if err := namespaces.Check("cassandradatacenter", key.String(), key.Namespace); err != nil {
+		return err
+	}
+
+	cassdc := &cassdcv1beta1.CassandraDatacenter{}
+	if err := c.Get(ctx, key, cassdc); err != nil {
+		return fmt.Errorf("failed to get cassandradatacenter %s: %w", key, err)
+	}
+
+	cassdcs := &cassdcv1beta1.CassandraDatacenterList{}
+	if err := c.List(ctx, cassdcs, client.InNamespace(key.Namespace)); err != nil {
+		return fmt.Errorf("failed to list cassandradatacenters in namespace %s: %w", key.Namespace, err)
+	}
+
+	config.DeriveCassandraBackend(backend, reaper.Namespace, cassdc, cassdcs.Items)
+
+	return nil
+}
+
+// Returns the requests for the Reapers in the namespace of the secret that reference it.
+func (r *ReaperReconciler) mapSecretToReapers(obj handler.MapObject) []ctrl.Request {
+	reapers := &api.ReaperList{}
+	if err := r.List(context.Background(), reapers, client.InNamespace(obj.Meta.GetNamespace()), client.MatchingFields{secretRefIndexField: obj.Meta.GetName()}); err != nil {
+		r.Log.Error(err, "failed to list reapers that reference secret", "secret", types.NamespacedName{Namespace: obj.Meta.GetNamespace(), Name: obj.Meta.GetName()})
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(reapers.Items))
+	for _, reaper := range reapers.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: reaper.Namespace, Name: reaper.Name}})
+	}
+	return requests
+}
+
 func (r *ReaperReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &api.Reaper{}, secretRefIndexField, func(obj runtime.Object) []string {
+		return reconcile.GetSecretNames(obj.(*api.Reaper))
+	})
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&api.Reaper{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&batchv1.Job{}).
+		Owns(&corev1.Service{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.mapSecretToReapers)}).
 		Complete(r)
 }
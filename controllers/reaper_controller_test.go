@@ -10,6 +10,7 @@ import (
 	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
 	mlabels "github.com/thelastpickle/reaper-operator/pkg/labels"
 	"github.com/thelastpickle/reaper-operator/pkg/reconcile"
+	"github.com/thelastpickle/reaper-operator/pkg/status"
 	appsv1 "k8s.io/api/apps/v1"
 	v1batch "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -107,6 +108,14 @@ var _ = Describe("Reaper controller", func() {
 
 		verifyReaperReady(types.NamespacedName{Namespace: ReaperNamespace, Name: ReaperName})
 
+		By("check that the reaper conditions are set")
+		readyReaper := &api.Reaper{}
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{Namespace: ReaperNamespace, Name: ReaperName}, readyReaper)).Should(Succeed())
+		for _, condType := range []api.ReaperConditionType{api.ConfigValid, api.ServiceReady, api.SchemaInitialized, api.DeploymentAvailable} {
+			Expect(status.IsConditionTrue(&readyReaper.Status, condType)).Should(BeTrue(), "condition %s should be true", condType)
+		}
+		Expect(readyReaper.Status.ObservedGeneration).Should(Equal(readyReaper.Generation))
+
 		// Now simulate the Reaper app entering a state in which its readiness probe fails. This
 		// should cause the deployment to have its status updated. The Reaper object's .Status.Ready
 		// field should subsequently be updated.
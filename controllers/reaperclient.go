@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"github.com/thelastpickle/reaper-operator/pkg/reaperclient"
+	"github.com/thelastpickle/reaper-operator/pkg/reconcile"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReaperClientFactory creates a REST client for the given Reaper instance. It can be replaced
+// in tests.
+type ReaperClientFactory func(ctx context.Context, c client.Client, reaper *api.Reaper) (reaperclient.Client, error)
+
+// NewReaperClient creates a REST client that talks to Reaper through its service. The client
+// logs in with the credentials from the uiAuth secret when the Reaper has authentication
+// enabled.
+func NewReaperClient(ctx context.Context, c client.Client, reaper *api.Reaper) (reaperclient.Client, error) {
+	// Include the namespace in case Reaper is deployed in a different namespace than the
+	// object that references it.
+	reaperSvc := reconcile.GetServiceName(reaper.Name) + "." + reaper.Namespace
+	baseURL := fmt.Sprintf("http://%s:8080", reaperSvc)
+
+	if reaper.Spec.UIAuth == nil {
+		return reaperclient.NewClient(baseURL)
+	}
+
+	secretKey := types.NamespacedName{Namespace: reaper.Namespace, Name: reaper.Spec.UIAuth.SecretRef.Name}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, secretKey, secret); err != nil {
+		return nil, fmt.Errorf("failed to get ui auth secret %s: %w", secretKey, err)
+	}
+
+	username, found := secret.Data["username"]
+	if !found {
+		return nil, fmt.Errorf("username key not found in ui auth secret %s", secretKey)
+	}
+	password, found := secret.Data["password"]
+	if !found {
+		return nil, fmt.Errorf("password key not found in ui auth secret %s", secretKey)
+	}
+
+	return reaperclient.NewAuthenticatedClient(baseURL, reaperclient.Credentials{Username: string(username), Password: string(password)})
+}
+
+func getReaperRefKey(ref api.ReaperReference, namespace string) types.NamespacedName {
+	if len(ref.Namespace) > 0 {
+		namespace = ref.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}
+}
+
+func newRepairOptions(params api.RepairParameters) reaperclient.RepairOptions {
+	owner := params.Owner
+	if len(owner) == 0 {
+		owner = api.DefaultRepairOwner
+	}
+
+	return reaperclient.RepairOptions{
+		ClusterName:         params.ClusterName,
+		Keyspace:            params.Keyspace,
+		Owner:               owner,
+		Tables:              params.Tables,
+		Intensity:           params.Intensity,
+		RepairParallelism:   params.RepairParallelism,
+		SegmentCountPerNode: params.SegmentCountPerNode,
+		IncrementalRepair:   params.IncrementalRepair,
+	}
+}
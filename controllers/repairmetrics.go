@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"github.com/thelastpickle/reaper-operator/pkg/metrics"
+	"github.com/thelastpickle/reaper-operator/pkg/reaperclient"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const DefaultRepairMetricsInterval = 1 * time.Minute
+
+var repairMetricsInterval = getReconcileDelay("REPAIR_METRICS_INTERVAL", DefaultRepairMetricsInterval)
+
+// RepairMetricsPoller periodically fetches the repair runs and schedules of the clusters that
+// are registered with the Reapers that are ready, and updates the repair metrics of the
+// clusters. It runs with the manager.
+type RepairMetricsPoller struct {
+	client.Client
+	Log                 logr.Logger
+	ReaperClientFactory ReaperClientFactory
+
+	// The clusters whose metrics were set by the last poll.
+	clusters map[metrics.ClusterKey]bool
+}
+
+func (p *RepairMetricsPoller) Start(stop <-chan struct{}) error {
+	wait.Until(p.poll, repairMetricsInterval, stop)
+	return nil
+}
+
+// Updates the repair metrics of the registered clusters and deletes the metrics of the clusters
+// that are no longer registered. The metrics of a cluster are left as they are if Reaper cannot
+// be polled.
+func (p *RepairMetricsPoller) poll() {
+	ctx := context.Background()
+
+	reapers := &api.ReaperList{}
+	if err := p.List(ctx, reapers); err != nil {
+		p.Log.Error(err, "failed to list reapers")
+		return
+	}
+
+	clusters := make(map[metrics.ClusterKey]bool)
+	for i := range reapers.Items {
+		reaper := &reapers.Items[i]
+		if !reaper.Status.Ready || len(reaper.Status.Clusters) == 0 {
+			continue
+		}
+
+		reaperKey := types.NamespacedName{Namespace: reaper.Namespace, Name: reaper.Name}
+		restClient, err := p.ReaperClientFactory(ctx, p.Client, reaper)
+		if err != nil {
+			p.Log.Error(err, "failed to create reaper rest client", "reaper", reaperKey)
+		}
+
+		for _, cluster := range reaper.Status.Clusters {
+			key := metrics.ClusterKey{Namespace: reaper.Namespace, Reaper: reaper.Name, Cluster: cluster}
+			clusters[key] = true
+			if restClient == nil {
+				continue
+			}
+
+			repairs, err := getClusterRepairs(ctx, restClient, cluster)
+			if err != nil {
+				p.Log.Error(err, "failed to poll repairs of cluster", "reaper", reaperKey, "cluster", cluster)
+				continue
+			}
+			metrics.SetClusterRepairs(key, repairs, time.Now())
+		}
+	}
+
+	for key := range p.clusters {
+		if !clusters[key] {
+			metrics.DeleteClusterRepairs(key)
+		}
+	}
+	p.clusters = clusters
+}
+
+func getClusterRepairs(ctx context.Context, restClient reaperclient.Client, cluster string) (metrics.ClusterRepairs, error) {
+	repairs := metrics.ClusterRepairs{}
+
+	runs, err := restClient.GetRepairRuns(ctx, cluster, "RUNNING", "DONE")
+	if err != nil {
+		return repairs, err
+	}
+
+	schedules, err := restClient.GetRepairSchedules(ctx, cluster)
+	if err != nil {
+		return repairs, err
+	}
+
+	for _, run := range runs {
+		if run.State == "RUNNING" {
+			repairs.RunningRepairs++
+		} else if endTime, err := time.Parse(time.RFC3339, run.EndTime); err == nil && endTime.After(repairs.LastSuccessfulRepair) {
+			repairs.LastSuccessfulRepair = endTime
+		}
+	}
+	repairs.RepairSchedules = len(schedules)
+
+	return repairs, nil
+}
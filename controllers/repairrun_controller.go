@@ -0,0 +1,228 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/thelastpickle/reaper-operator/pkg/reaperclient"
+	"github.com/thelastpickle/reaper-operator/pkg/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+)
+
+const (
+	repairRunFinalizer = "reaper.cassandra-reaper.io/repair-run"
+)
+
+// RepairRunReconciler reconciles a RepairRun object
+type RepairRunReconciler struct {
+	client.Client
+	Log                 logr.Logger
+	Scheme              *runtime.Scheme
+	ReaperClientFactory ReaperClientFactory
+}
+
+// +kubebuilder:rbac:groups=reaper.cassandra-reaper.io,resources=repairruns,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=reaper.cassandra-reaper.io,resources=repairruns/status,verbs=get;update;patch
+
+func (r *RepairRunReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	reqLogger := r.Log.WithValues("repairrun", req.NamespacedName)
+	statusManager := &status.StatusManager{Client: r.Client}
+
+	instance := &api.RepairRun{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	run := instance.DeepCopy()
+
+	if run.DeletionTimestamp != nil {
+		return r.abortRun(ctx, run, reqLogger)
+	}
+
+	if run.Status.State.IsTerminated() {
+		// There is nothing left to do. If the resource was left behind with a finalizer, it will
+		// be removed on deletion.
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(run, repairRunFinalizer) {
+		controllerutil.AddFinalizer(run, repairRunFinalizer)
+		if err = r.Update(ctx, run); err != nil {
+			reqLogger.Error(err, "failed to add finalizer")
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	reaperKey := getReaperRefKey(run.Spec.Reaper, run.Namespace)
+	reaper := &api.Reaper{}
+	if err = r.Get(ctx, reaperKey, reaper); err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("reaper instance not found", "reaper", reaperKey)
+			return ctrl.Result{RequeueAfter: longDelay}, nil
+		}
+		reqLogger.Error(err, "failed to retrieve reaper instance", "reaper", reaperKey)
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	if !reaper.Status.Ready {
+		reqLogger.Info("waiting for reaper to become ready", "reaper", reaperKey)
+		return ctrl.Result{RequeueAfter: shortDelay}, nil
+	}
+
+	restClient, err := r.ReaperClientFactory(ctx, r.Client, reaper)
+	if err != nil {
+		reqLogger.Error(err, "failed to create reaper rest client", "reaper", reaperKey)
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	var reaperRun *reaperclient.RepairRun
+
+	if len(run.Status.RunID) == 0 {
+		reqLogger.Info("creating repair run", "reaper", reaperKey)
+		if reaperRun, err = restClient.CreateRepairRun(ctx, newRepairOptions(run.Spec.RepairParameters)); err != nil {
+			reqLogger.Error(err, "failed to create repair run", "reaper", reaperKey)
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+
+		// Record the id right away so that a failure to start the run does not result in a
+		// second run getting created.
+		if err = statusManager.SetRepairRunStatus(ctx, run, newRepairRunStatus(reaperRun)); err != nil {
+			reqLogger.Error(err, "failed to update status")
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+	} else {
+		reaperRun, err = restClient.GetRepairRun(ctx, run.Status.RunID)
+		if err == reaperclient.RepairRunNotFound {
+			// The run is gone from Reaper, e.g., Reaper uses the memory backend and was
+			// restarted. It is reported as deleted rather than resubmitted.
+			reqLogger.Info("repair run not found in reaper", "runId", run.Status.RunID)
+			runStatus := run.Status
+			runStatus.State = api.RepairRunDeleted
+			if err = statusManager.SetRepairRunStatus(ctx, run, runStatus); err != nil {
+				reqLogger.Error(err, "failed to update status")
+				return ctrl.Result{RequeueAfter: shortDelay}, err
+			}
+			return ctrl.Result{}, nil
+		} else if err != nil {
+			reqLogger.Error(err, "failed to get repair run", "runId", run.Status.RunID)
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+	}
+
+	if api.RepairRunState(reaperRun.State) == api.RepairRunNotStarted {
+		reqLogger.Info("starting repair run", "runId", reaperRun.Id)
+		if reaperRun, err = restClient.UpdateRepairRunState(ctx, reaperRun.Id, string(api.RepairRunRunning)); err != nil {
+			reqLogger.Error(err, "failed to start repair run", "runId", run.Status.RunID)
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+	}
+
+	runStatus := newRepairRunStatus(reaperRun)
+	if err = statusManager.SetRepairRunStatus(ctx, run, runStatus); err != nil {
+		reqLogger.Error(err, "failed to update status")
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	if runStatus.State.IsTerminated() {
+		reqLogger.Info("repair run finished", "runId", runStatus.RunID, "state", runStatus.State)
+		return ctrl.Result{}, nil
+	}
+
+	// Keep polling Reaper to report progress until the run finishes.
+	return ctrl.Result{RequeueAfter: shortDelay}, nil
+}
+
+// Aborts the repair run in Reaper if it is still in progress and then removes the finalizer.
+// The run is fetched first since it may have finished or been aborted in Reaper since the
+// status was last updated.
+func (r *RepairRunReconciler) abortRun(ctx context.Context, run *api.RepairRun, reqLogger logr.Logger) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(run, repairRunFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if len(run.Status.RunID) > 0 && !run.Status.State.IsTerminated() {
+		reaperKey := getReaperRefKey(run.Spec.Reaper, run.Namespace)
+		reaper := &api.Reaper{}
+		err := r.Get(ctx, reaperKey, reaper)
+		if err == nil {
+			restClient, err := r.ReaperClientFactory(ctx, r.Client, reaper)
+			if err != nil {
+				reqLogger.Error(err, "failed to create reaper rest client", "reaper", reaperKey)
+				return ctrl.Result{RequeueAfter: shortDelay}, err
+			}
+
+			reaperRun, err := restClient.GetRepairRun(ctx, run.Status.RunID)
+			if err != nil && err != reaperclient.RepairRunNotFound {
+				reqLogger.Error(err, "failed to get repair run", "runId", run.Status.RunID)
+				return ctrl.Result{RequeueAfter: shortDelay}, err
+			}
+
+			if reaperRun != nil && !api.RepairRunState(reaperRun.State).IsTerminated() {
+				reqLogger.Info("aborting repair run", "runId", run.Status.RunID)
+				_, err = restClient.UpdateRepairRunState(ctx, run.Status.RunID, string(api.RepairRunAborted))
+				if err != nil && err != reaperclient.RepairRunNotFound {
+					reqLogger.Error(err, "failed to abort repair run", "runId", run.Status.RunID)
+					return ctrl.Result{RequeueAfter: shortDelay}, err
+				}
+			}
+		} else if !errors.IsNotFound(err) {
+			reqLogger.Error(err, "failed to retrieve reaper instance", "reaper", reaperKey)
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(run, repairRunFinalizer)
+	if err := r.Update(ctx, run); err != nil {
+		reqLogger.Error(err, "failed to remove finalizer")
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func newRepairRunStatus(run *reaperclient.RepairRun) api.RepairRunStatus {
+	return api.RepairRunStatus{
+		RunID:            run.Id,
+		State:            api.RepairRunState(run.State),
+		SegmentsRepaired: run.SegmentsRepaired,
+		TotalSegments:    run.TotalSegments,
+		CreationTime:     run.CreationTime,
+		StartTime:        run.StartTime,
+		EndTime:          run.EndTime,
+		LastEvent:        run.LastEvent,
+	}
+}
+
+func (r *RepairRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&api.RepairRun{}).
+		Complete(r)
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"github.com/thelastpickle/reaper-operator/pkg/reaperclient"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Returns a RepairRun that is being deleted and whose status reports the run as running.
+func newDeletedRepairRun(runID string) *api.RepairRun {
+	now := metav1.Now()
+	return &api.RepairRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         repairTestNamespace,
+			Name:              "run",
+			Finalizers:        []string{repairRunFinalizer},
+			DeletionTimestamp: &now,
+		},
+		Spec: api.RepairRunSpec{
+			RepairParameters: api.RepairParameters{
+				Reaper:      api.ReaperReference{Name: "reaper"},
+				ClusterName: "test",
+				Keyspace:    "ks",
+			},
+		},
+		Status: api.RepairRunStatus{RunID: runID, State: api.RepairRunRunning},
+	}
+}
+
+func reconcileDeletedRepairRun(t *testing.T, restClient *fakeReaperClient, run *api.RepairRun) *api.RepairRun {
+	r := &RepairRunReconciler{
+		Client:              fake.NewFakeClientWithScheme(newTestScheme(), newReadyReaper(), run),
+		Log:                 ctrl.Log.WithName("controllers").WithName("RepairRun"),
+		ReaperClientFactory: restClient.factory,
+	}
+	key := types.NamespacedName{Namespace: run.Namespace, Name: run.Name}
+
+	if _, err := r.Reconcile(ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("failed to reconcile repair run: %s", err)
+	}
+
+	updated := &api.RepairRun{}
+	if err := r.Get(context.Background(), key, updated); err != nil {
+		t.Fatalf("failed to get repair run: %s", err)
+	}
+	return updated
+}
+
+func TestRepairRunDelete(t *testing.T) {
+	restClient := newFakeReaperClient()
+	restClient.runs["1"] = &reaperclient.RepairRun{Id: "1", State: string(api.RepairRunRunning)}
+
+	run := reconcileDeletedRepairRun(t, restClient, newDeletedRepairRun("1"))
+
+	if state := restClient.runs["1"].State; state != string(api.RepairRunAborted) {
+		t.Errorf("expected repair run to be aborted, got (%s)", state)
+	}
+	if len(run.Finalizers) != 0 {
+		t.Errorf("expected finalizer to be removed, got (%v)", run.Finalizers)
+	}
+}
+
+func TestRepairRunDeleteAlreadyAborted(t *testing.T) {
+	restClient := newFakeReaperClient()
+	// The run was aborted in Reaper after the status was last updated.
+	restClient.runs["1"] = &reaperclient.RepairRun{Id: "1", State: string(api.RepairRunAborted)}
+
+	run := reconcileDeletedRepairRun(t, restClient, newDeletedRepairRun("1"))
+
+	if len(run.Finalizers) != 0 {
+		t.Errorf("expected finalizer to be removed, got (%v)", run.Finalizers)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/thelastpickle/reaper-operator/pkg/reaperclient"
+	"github.com/thelastpickle/reaper-operator/pkg/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+)
+
+const (
+	repairScheduleFinalizer = "reaper.cassandra-reaper.io/repair-schedule"
+)
+
+// RepairScheduleReconciler reconciles a RepairSchedule object
+type RepairScheduleReconciler struct {
+	client.Client
+	Log                 logr.Logger
+	Scheme              *runtime.Scheme
+	ReaperClientFactory ReaperClientFactory
+}
+
+// +kubebuilder:rbac:groups=reaper.cassandra-reaper.io,resources=repairschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=reaper.cassandra-reaper.io,resources=repairschedules/status,verbs=get;update;patch
+
+func (r *RepairScheduleReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	reqLogger := r.Log.WithValues("repairschedule", req.NamespacedName)
+	statusManager := &status.StatusManager{Client: r.Client}
+
+	instance := &api.RepairSchedule{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	schedule := instance.DeepCopy()
+
+	if schedule.DeletionTimestamp != nil {
+		return r.deleteSchedule(ctx, schedule, reqLogger)
+	}
+
+	if !controllerutil.ContainsFinalizer(schedule, repairScheduleFinalizer) {
+		controllerutil.AddFinalizer(schedule, repairScheduleFinalizer)
+		if err = r.Update(ctx, schedule); err != nil {
+			reqLogger.Error(err, "failed to add finalizer")
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	reaperKey := getReaperRefKey(schedule.Spec.Reaper, schedule.Namespace)
+	reaper := &api.Reaper{}
+	if err = r.Get(ctx, reaperKey, reaper); err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.Info("reaper instance not found", "reaper", reaperKey)
+			return ctrl.Result{RequeueAfter: longDelay}, nil
+		}
+		reqLogger.Error(err, "failed to retrieve reaper instance", "reaper", reaperKey)
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	if !reaper.Status.Ready {
+		reqLogger.Info("waiting for reaper to become ready", "reaper", reaperKey)
+		return ctrl.Result{RequeueAfter: shortDelay}, nil
+	}
+
+	restClient, err := r.ReaperClientFactory(ctx, r.Client, reaper)
+	if err != nil {
+		reqLogger.Error(err, "failed to create reaper rest client", "reaper", reaperKey)
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	var reaperSchedule *reaperclient.RepairSchedule
+
+	if len(schedule.Status.ScheduleID) > 0 {
+		if schedule.Status.ObservedGeneration != schedule.Generation {
+			// Reaper does not support updating the parameters of a schedule, so it has to be
+			// replaced.
+			reqLogger.Info("spec changed, deleting repair schedule so it can be recreated", "scheduleId", schedule.Status.ScheduleID)
+			if err = deleteReaperSchedule(ctx, restClient, schedule); err != nil && err != reaperclient.RepairScheduleNotFound {
+				reqLogger.Error(err, "failed to delete repair schedule", "scheduleId", schedule.Status.ScheduleID)
+				return ctrl.Result{RequeueAfter: shortDelay}, err
+			}
+		} else {
+			reaperSchedule, err = restClient.GetRepairSchedule(ctx, schedule.Status.ScheduleID)
+			if err == reaperclient.RepairScheduleNotFound {
+				// This can happen when Reaper uses the memory backend and has been restarted.
+				reqLogger.Info("repair schedule not found in reaper, it will be recreated", "scheduleId", schedule.Status.ScheduleID)
+			} else if err != nil {
+				reqLogger.Error(err, "failed to get repair schedule", "scheduleId", schedule.Status.ScheduleID)
+				return ctrl.Result{RequeueAfter: shortDelay}, err
+			}
+		}
+	}
+
+	owner := schedule.Status.Owner
+	if reaperSchedule == nil {
+		reqLogger.Info("creating repair schedule", "reaper", reaperKey)
+		options := newRepairOptions(schedule.Spec.RepairParameters)
+		reaperSchedule, err = restClient.CreateRepairSchedule(ctx, reaperclient.RepairScheduleOptions{
+			RepairOptions:       options,
+			ScheduleDaysBetween: schedule.Spec.ScheduleDaysBetween,
+			ScheduleTriggerTime: schedule.Spec.ScheduleTriggerTime,
+		})
+		if err != nil {
+			reqLogger.Error(err, "failed to create repair schedule", "reaper", reaperKey)
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+		owner = options.Owner
+	}
+
+	scheduleStatus := api.RepairScheduleStatus{
+		ScheduleID:         reaperSchedule.Id,
+		State:              reaperSchedule.State,
+		NextActivation:     reaperSchedule.NextActivation,
+		ObservedGeneration: schedule.Generation,
+		Owner:              owner,
+	}
+	if err = statusManager.SetRepairScheduleStatus(ctx, schedule, scheduleStatus); err != nil {
+		reqLogger.Error(err, "failed to update status")
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	// Requeue to periodically refresh the next activation time and to recreate the schedule
+	// if it is removed from Reaper.
+	return ctrl.Result{RequeueAfter: longDelay}, nil
+}
+
+func (r *RepairScheduleReconciler) deleteSchedule(ctx context.Context, schedule *api.RepairSchedule, reqLogger logr.Logger) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(schedule, repairScheduleFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if len(schedule.Status.ScheduleID) > 0 {
+		reaperKey := getReaperRefKey(schedule.Spec.Reaper, schedule.Namespace)
+		reaper := &api.Reaper{}
+		err := r.Get(ctx, reaperKey, reaper)
+		if err == nil {
+			restClient, err := r.ReaperClientFactory(ctx, r.Client, reaper)
+			if err != nil {
+				reqLogger.Error(err, "failed to create reaper rest client", "reaper", reaperKey)
+				return ctrl.Result{RequeueAfter: shortDelay}, err
+			}
+
+			reqLogger.Info("deleting repair schedule", "scheduleId", schedule.Status.ScheduleID)
+			if err = deleteReaperSchedule(ctx, restClient, schedule); err != nil && err != reaperclient.RepairScheduleNotFound {
+				reqLogger.Error(err, "failed to delete repair schedule", "scheduleId", schedule.Status.ScheduleID)
+				return ctrl.Result{RequeueAfter: shortDelay}, err
+			}
+		} else if !errors.IsNotFound(err) {
+			reqLogger.Error(err, "failed to retrieve reaper instance", "reaper", reaperKey)
+			return ctrl.Result{RequeueAfter: shortDelay}, err
+		}
+		// If the Reaper instance is gone, then so is the schedule.
+	}
+
+	controllerutil.RemoveFinalizer(schedule, repairScheduleFinalizer)
+	if err := r.Update(ctx, schedule); err != nil {
+		reqLogger.Error(err, "failed to remove finalizer")
+		return ctrl.Result{RequeueAfter: shortDelay}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// Deletes the schedule in Reaper. It is paused first since Reaper does not delete active
+// schedules, and it is deleted with the owner that it was created with in case spec's owner
+// has changed since.
+func deleteReaperSchedule(ctx context.Context, restClient reaperclient.Client, schedule *api.RepairSchedule) error {
+	owner := schedule.Status.Owner
+	if len(owner) == 0 {
+		// The owner is not recorded for schedules that were created by older versions.
+		owner = newRepairOptions(schedule.Spec.RepairParameters).Owner
+	}
+
+	if err := restClient.PauseRepairSchedule(ctx, schedule.Status.ScheduleID); err != nil {
+		return err
+	}
+	return restClient.DeleteRepairSchedule(ctx, schedule.Status.ScheduleID, owner)
+}
+
+func (r *RepairScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&api.RepairSchedule{}).
+		Complete(r)
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"github.com/thelastpickle/reaper-operator/pkg/reaperclient"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const repairTestNamespace = "repair-test"
+
+func newReadyReaper() *api.Reaper {
+	return &api.Reaper{
+		ObjectMeta: metav1.ObjectMeta{Namespace: repairTestNamespace, Name: "reaper"},
+		Status:     api.ReaperStatus{Ready: true},
+	}
+}
+
+// Returns a RepairSchedule whose spec has changed since the schedule in Reaper was created by
+// reaper-operator, including the owner.
+func newChangedRepairSchedule(scheduleID string) *api.RepairSchedule {
+	return &api.RepairSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  repairTestNamespace,
+			Name:       "schedule",
+			Generation: 2,
+			Finalizers: []string{repairScheduleFinalizer},
+		},
+		Spec: api.RepairScheduleSpec{
+			RepairParameters: api.RepairParameters{
+				Reaper:      api.ReaperReference{Name: "reaper"},
+				ClusterName: "test",
+				Keyspace:    "ks",
+				Owner:       "new-owner",
+			},
+		},
+		Status: api.RepairScheduleStatus{
+			ScheduleID:         scheduleID,
+			State:              "ACTIVE",
+			ObservedGeneration: 1,
+			Owner:              api.DefaultRepairOwner,
+		},
+	}
+}
+
+func newRepairScheduleReconciler(restClient *fakeReaperClient, objs ...runtime.Object) *RepairScheduleReconciler {
+	return &RepairScheduleReconciler{
+		Client:              fake.NewFakeClientWithScheme(newTestScheme(), objs...),
+		Log:                 ctrl.Log.WithName("controllers").WithName("RepairSchedule"),
+		ReaperClientFactory: restClient.factory,
+	}
+}
+
+func TestRepairScheduleUpdate(t *testing.T) {
+	restClient := newFakeReaperClient()
+	active, _ := restClient.CreateRepairSchedule(context.Background(), reaperclient.RepairScheduleOptions{
+		RepairOptions: reaperclient.RepairOptions{ClusterName: "test", Keyspace: "ks", Owner: api.DefaultRepairOwner},
+	})
+
+	schedule := newChangedRepairSchedule(active.Id)
+	r := newRepairScheduleReconciler(restClient, newReadyReaper(), schedule)
+	key := types.NamespacedName{Namespace: schedule.Namespace, Name: schedule.Name}
+
+	if _, err := r.Reconcile(ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("failed to reconcile repair schedule: %s", err)
+	}
+
+	if _, found := restClient.schedules[active.Id]; found {
+		t.Errorf("expected repair schedule (%s) to be deleted", active.Id)
+	}
+
+	updated := &api.RepairSchedule{}
+	if err := r.Get(context.Background(), key, updated); err != nil {
+		t.Fatalf("failed to get repair schedule: %s", err)
+	}
+	if updated.Status.ScheduleID == active.Id || restClient.schedules[updated.Status.ScheduleID] == nil {
+		t.Errorf("expected a new repair schedule, got (%s)", updated.Status.ScheduleID)
+	}
+	if updated.Status.Owner != "new-owner" {
+		t.Errorf("expected owner (new-owner), got (%s)", updated.Status.Owner)
+	}
+	if updated.Status.ObservedGeneration != 2 {
+		t.Errorf("expected observed generation (2), got (%d)", updated.Status.ObservedGeneration)
+	}
+}
+
+func TestRepairScheduleDelete(t *testing.T) {
+	restClient := newFakeReaperClient()
+	active, _ := restClient.CreateRepairSchedule(context.Background(), reaperclient.RepairScheduleOptions{
+		RepairOptions: reaperclient.RepairOptions{ClusterName: "test", Keyspace: "ks", Owner: api.DefaultRepairOwner},
+	})
+
+	schedule := newChangedRepairSchedule(active.Id)
+	now := metav1.Now()
+	schedule.DeletionTimestamp = &now
+	r := newRepairScheduleReconciler(restClient, newReadyReaper(), schedule)
+	key := types.NamespacedName{Namespace: schedule.Namespace, Name: schedule.Name}
+
+	if _, err := r.Reconcile(ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("failed to reconcile repair schedule: %s", err)
+	}
+
+	if len(restClient.schedules) != 0 {
+		t.Errorf("expected repair schedule (%s) to be deleted", active.Id)
+	}
+
+	deleted := &api.RepairSchedule{}
+	if err := r.Get(context.Background(), key, deleted); err != nil {
+		t.Fatalf("failed to get repair schedule: %s", err)
+	}
+	if len(deleted.Finalizers) != 0 {
+		t.Errorf("expected finalizer to be removed, got (%v)", deleted.Finalizers)
+	}
+}
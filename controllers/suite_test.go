@@ -77,7 +77,7 @@ var _ = BeforeSuite(func(done Done) {
 	})
 	Expect(err).ToNot(HaveOccurred())
 
-	reconcile.InitReconcilers(k8sManager.GetClient(), k8sManager.GetScheme())
+	reconcile.InitReconcilers(k8sManager.GetClient(), k8sManager.GetScheme(), k8sManager.GetEventRecorderFor("reaper-operator"))
 
 	err = (&ReaperReconciler{
 		Client:               k8sManager.GetClient(),
@@ -85,7 +85,10 @@ var _ = BeforeSuite(func(done Done) {
 		ServiceReconciler:    reconcile.GetServiceReconciler(),
 		DeploymentReconciler: reconcile.GetDeploymentReconciler(),
 		SchemaReconciler:     reconcile.GetSchemaReconciler(),
+		MonitoringReconciler: reconcile.GetMonitoringReconciler(),
 		Validator:            config.NewValidator(),
+		ReaperClientFactory:  NewReaperClient,
+		Recorder:             k8sManager.GetEventRecorderFor("reaper-operator"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
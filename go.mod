@@ -9,11 +9,13 @@ require (
 	github.com/jsanda/reaper-client-go v0.2.1-0.20201029201014-86b331710113
 	github.com/onsi/ginkgo v1.12.1
 	github.com/onsi/gomega v1.10.1
+	github.com/prometheus/client_golang v1.5.1
 	github.com/stretchr/testify v1.6.1
 	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 	k8s.io/api v0.18.6
 	k8s.io/apimachinery v0.18.6
 	k8s.io/client-go v12.0.0+incompatible
+	k8s.io/kubernetes v1.17.4
 	sigs.k8s.io/controller-runtime v0.6.2
 )
 
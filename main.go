@@ -18,7 +18,6 @@ package main
 
 import (
 	"flag"
-	"fmt"
 	"os"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -26,10 +25,12 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/thelastpickle/reaper-operator/pkg/config"
 	"github.com/thelastpickle/reaper-operator/pkg/reconcile"
+	"github.com/thelastpickle/reaper-operator/pkg/webhooks"
 
 	reaperv1alpha1 "github.com/thelastpickle/reaper-operator/api/v1alpha1"
 	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
@@ -61,26 +62,35 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
 
-	watchNamespace, err := getWatchNamespace()
+	watchNamespaces, err := config.GetWatchNamespaces()
 	if err != nil {
 		setupLog.Error(err, "unable to get WatchNamespace, "+
 			"the manager will watch and manage resources in all namespaces")
 	}
+	setupLog.Info("watching namespaces", "namespaces", watchNamespaces.String())
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	options := ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
 		Port:               9443,
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "b5b68f22.cassandra-reaper.io",
-		Namespace:          watchNamespace,
-	})
+	}
+	// A single namespace is watched with a namespaced cache and several namespaces with a
+	// cache per namespace. An empty Namespace watches all namespaces.
+	if len(watchNamespaces) == 1 {
+		options.Namespace = watchNamespaces[0]
+	} else if len(watchNamespaces) > 1 {
+		options.NewCache = cache.MultiNamespacedCacheBuilder(watchNamespaces)
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	reconcile.InitReconcilers(mgr.GetClient(), mgr.GetScheme())
+	reconcile.InitReconcilers(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("reaper-operator"))
 
 	if err = (&controllers.ReaperReconciler{
 		Client:               mgr.GetClient(),
@@ -89,19 +99,56 @@ func main() {
 		ServiceReconciler:    reconcile.GetServiceReconciler(),
 		DeploymentReconciler: reconcile.GetDeploymentReconciler(),
 		SchemaReconciler:     reconcile.GetSchemaReconciler(),
+		MonitoringReconciler: reconcile.GetMonitoringReconciler(),
 		Validator:            config.NewValidator(),
+		ReaperClientFactory:  controllers.NewReaperClient,
+		Recorder:             mgr.GetEventRecorderFor("reaper-operator"),
+		WatchNamespaces:      watchNamespaces,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Reaper")
 		os.Exit(1)
 	}
 	if err = (&controllers.CassandraDatacenterReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("CassandraDatacenter"),
-		Scheme: mgr.GetScheme(),
+		Client:              mgr.GetClient(),
+		Log:                 ctrl.Log.WithName("controllers").WithName("CassandraDatacenter"),
+		Scheme:              mgr.GetScheme(),
+		ReaperClientFactory: controllers.NewReaperClient,
+		SidecarReconciler:   reconcile.GetSidecarReconciler(),
+		Recorder:            mgr.GetEventRecorderFor("reaper-operator"),
+		WatchNamespaces:     watchNamespaces,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CassandraDatacenter")
 		os.Exit(1)
 	}
+	if err = (&controllers.RepairScheduleReconciler{
+		Client:              mgr.GetClient(),
+		Log:                 ctrl.Log.WithName("controllers").WithName("RepairSchedule"),
+		Scheme:              mgr.GetScheme(),
+		ReaperClientFactory: controllers.NewReaperClient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RepairSchedule")
+		os.Exit(1)
+	}
+	if err = (&controllers.RepairRunReconciler{
+		Client:              mgr.GetClient(),
+		Log:                 ctrl.Log.WithName("controllers").WithName("RepairRun"),
+		Scheme:              mgr.GetScheme(),
+		ReaperClientFactory: controllers.NewReaperClient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RepairRun")
+		os.Exit(1)
+	}
+	if err = mgr.Add(&controllers.RepairMetricsPoller{
+		Client:              mgr.GetClient(),
+		Log:                 ctrl.Log.WithName("controllers").WithName("RepairMetrics"),
+		ReaperClientFactory: controllers.NewReaperClient,
+	}); err != nil {
+		setupLog.Error(err, "unable to add repair metrics poller")
+		os.Exit(1)
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		webhooks.SetupReaperWebhooks(mgr, config.NewValidator())
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -110,17 +157,3 @@ func main() {
 		os.Exit(1)
 	}
 }
-
-// getWatchNamespace returns the Namespace the operator should be watching for changes
-func getWatchNamespace() (string, error) {
-	// WatchNamespaceEnvVar is the constant for env variable WATCH_NAMESPACE
-	// which specifies the Namespace to watch.
-	// An empty value means the operator is running with cluster scope.
-	var watchNamespaceEnvVar = "WATCH_NAMESPACE"
-
-	ns, found := os.LookupEnv(watchNamespaceEnvVar)
-	if !found {
-		return "", fmt.Errorf("%s must be set", watchNamespaceEnvVar)
-	}
-	return ns, nil
-}
@@ -5,9 +5,13 @@ import (
 	"strconv"
 	"strings"
 
+	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
 )
 
+// The highest replication factor that is derived for a data center.
+const maxDerivedReplicationFactor = 3
+
 func ReplicationToString(r api.ReplicationConfig) string {
 	if r.SimpleStrategy != nil {
 		replicationFactor := strconv.FormatInt(int64(*r.SimpleStrategy), 10)
@@ -26,3 +30,37 @@ func ReplicationToString(r api.ReplicationConfig) string {
 		return fmt.Sprintf("{'class': 'NetworkTopologyStrategy', %s}", strings.Join(dcs, ", "))
 	}
 }
+
+// Sets the cluster name, contact points and replication of the backend that are not set from
+// the referenced CassandraDatacenter. dcs are the CassandraDatacenters in the namespace of the
+// referenced one. Every data center of the same cluster gets a replication factor of
+// min(3, size). namespace is the namespace of the Reaper.
+func DeriveCassandraBackend(backend *api.CassandraBackend, namespace string, dc *cassdcv1beta1.CassandraDatacenter, dcs []cassdcv1beta1.CassandraDatacenter) {
+	if backend.ClusterName == "" {
+		backend.ClusterName = dc.Spec.ClusterName
+	}
+
+	if backend.CassandraService == "" {
+		backend.CassandraService = dc.GetDatacenterServiceName()
+		if dc.Namespace != namespace {
+			backend.CassandraService += "." + dc.Namespace
+		}
+	}
+
+	if backend.Replication == (api.ReplicationConfig{}) {
+		replication := map[string]int32{dc.Name: replicationFactor(dc.Spec.Size)}
+		for _, other := range dcs {
+			if other.Spec.ClusterName == dc.Spec.ClusterName && other.DeletionTimestamp == nil {
+				replication[other.Name] = replicationFactor(other.Spec.Size)
+			}
+		}
+		backend.Replication = api.ReplicationConfig{NetworkTopologyStrategy: &replication}
+	}
+}
+
+func replicationFactor(size int32) int32 {
+	if size > maxDerivedReplicationFactor {
+		return maxDerivedReplicationFactor
+	}
+	return size
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newDatacenter(namespace, name, clusterName string, size int32) cassdcv1beta1.CassandraDatacenter {
+	return cassdcv1beta1.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       cassdcv1beta1.CassandraDatacenterSpec{ClusterName: clusterName, Size: size},
+	}
+}
+
+func TestDeriveCassandraBackend(t *testing.T) {
+	dc1 := newDatacenter("cassandra", "dc1", "test", 5)
+	dcs := []cassdcv1beta1.CassandraDatacenter{
+		dc1,
+		newDatacenter("cassandra", "dc2", "test", 2),
+		newDatacenter("cassandra", "other", "other-cluster", 3),
+	}
+
+	backend := &api.CassandraBackend{}
+	DeriveCassandraBackend(backend, "reaper", &dc1, dcs)
+
+	if backend.ClusterName != "test" {
+		t.Errorf("expected cluster name (test), got (%s)", backend.ClusterName)
+	}
+	if expected := dc1.GetDatacenterServiceName() + ".cassandra"; backend.CassandraService != expected {
+		t.Errorf("expected contact points (%s), got (%s)", expected, backend.CassandraService)
+	}
+	expected := map[string]int32{"dc1": 3, "dc2": 2}
+	if backend.Replication.NetworkTopologyStrategy == nil || !reflect.DeepEqual(*backend.Replication.NetworkTopologyStrategy, expected) {
+		t.Errorf("expected replication (%v), got (%+v)", expected, backend.Replication)
+	}
+
+	// Settings in the spec are not overridden.
+	backend = &api.CassandraBackend{
+		CassandraService: "test-svc",
+		Replication:      api.ReplicationConfig{SimpleStrategy: int32Ptr(2)},
+	}
+	DeriveCassandraBackend(backend, "cassandra", &dc1, dcs)
+
+	if backend.CassandraService != "test-svc" {
+		t.Errorf("expected contact points (test-svc), got (%s)", backend.CassandraService)
+	}
+	if backend.Replication.NetworkTopologyStrategy != nil || *backend.Replication.SimpleStrategy != 2 {
+		t.Errorf("expected replication to be unchanged, got (%+v)", backend.Replication)
+	}
+}
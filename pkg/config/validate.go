@@ -2,6 +2,9 @@ package config
 
 import (
 	"errors"
+	"regexp"
+	"strconv"
+	"strings"
 
 	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
 )
@@ -9,13 +12,36 @@ import (
 type ValidationError error
 
 var (
-	ClusterNameRequired   ValidationError = errors.New("CassandraBackend.ClusterName is required")
-	ContactPointsRequired ValidationError = errors.New("CassandraBackend.ContactPoints is required")
+	ClusterNameRequired           ValidationError = errors.New("CassandraBackend.ClusterName is required")
+	ContactPointsRequired         ValidationError = errors.New("CassandraBackend.ContactPoints is required")
+	InvalidRepairIntensity        ValidationError = errors.New("RepairIntensity must be a number greater than 0 and less than or equal to 1")
+	InvalidRepairParallelism      ValidationError = errors.New("RepairParallelism must be one of SEQUENTIAL, PARALLEL, or DATACENTER_AWARE")
+	InvalidAutoSchedulingPeriod   ValidationError = errors.New("AutoScheduling periods must be ISO-8601 durations, e.g., PT10M")
+	ExcludedKeyspaceNameRequired  ValidationError = errors.New("AutoScheduling.ExcludedKeyspaces must not contain empty names")
+	StorageTypeImmutable          ValidationError = errors.New("StorageType cannot be changed")
+	KeyspaceImmutable             ValidationError = errors.New("CassandraBackend.Keyspace cannot be changed")
+	ReplicasRequireCassandra      ValidationError = errors.New("Replicas greater than 1 requires the cassandra StorageType")
+	TLSSecretRequired             ValidationError = errors.New("TLS.SecretRef.Name is required")
+	UIAuthSecretRequired          ValidationError = errors.New("UIAuth.SecretRef.Name is required")
+	DatacenterNameRequired        ValidationError = errors.New("CassandraBackend.CassandraDatacenterRef.Name is required")
+	AvailabilityRequiresCassandra ValidationError = errors.New("DatacenterAvailability EACH and SIDECAR require the cassandra StorageType")
+	EachRequiresNetworkTopology   ValidationError = errors.New("DatacenterAvailability EACH requires NetworkTopologyStrategy replication")
+)
+
+var (
+	repairParallelismValues = []string{"SEQUENTIAL", "PARALLEL", "DATACENTER_AWARE"}
+
+	// Matches ISO-8601 durations like the ones accepted by java.time.Duration and Period, e.g., PT15S or P1DT12H.
+	iso8601Duration = regexp.MustCompile(`^P(\d+Y)?(\d+M)?(\d+W)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
 )
 
 type Validator interface {
 	Validate(reaper *api.Reaper) error
 
+	// Checks that an update does not change any fields that cannot be changed once the
+	// Reaper has been deployed. Unset fields are compared with their defaults.
+	ValidateUpdate(old, new *api.Reaper) error
+
 	SetDefaults(reaper *api.Reaper) bool
 }
 
@@ -28,12 +54,62 @@ func NewValidator() Validator {
 func (v *validator) Validate(reaper *api.Reaper) error {
 	cfg := reaper.Spec.ServerConfig
 
+	if cfg.RepairIntensity != "" {
+		if intensity, err := strconv.ParseFloat(cfg.RepairIntensity, 64); err != nil || intensity <= 0 || intensity > 1 {
+			return InvalidRepairIntensity
+		}
+	}
+
+	if cfg.RepairParallelism != "" && !contains(repairParallelismValues, cfg.RepairParallelism) {
+		return InvalidRepairParallelism
+	}
+
+	if err := validateAutoScheduling(cfg.AutoScheduling); err != nil {
+		return err
+	}
+
+	if cfg.TLS != nil && cfg.TLS.SecretRef.Name == "" {
+		return TLSSecretRequired
+	}
+
+	if reaper.Spec.UIAuth != nil && reaper.Spec.UIAuth.SecretRef.Name == "" {
+		return UIAuthSecretRequired
+	}
+
+	requiresCassandra := cfg.DatacenterAvailability == api.DatacenterAvailabilityEach || cfg.DatacenterAvailability == api.DatacenterAvailabilitySidecar
+
 	if cfg.StorageType == "" || cfg.StorageType == api.StorageTypeMemory {
+		if requiresCassandra {
+			return AvailabilityRequiresCassandra
+		}
+
+		// Each Reaper instance would have its own in-memory state.
+		if reaper.Spec.Replicas != nil && *reaper.Spec.Replicas > 1 {
+			return ReplicasRequireCassandra
+		}
 		return nil
 	}
 
 	if cfg.StorageType == api.StorageTypeCassandra {
-		if cfg.CassandraBackend == nil || cfg.CassandraBackend.ClusterName == "" {
+		if cfg.CassandraBackend == nil {
+			return ClusterNameRequired
+		}
+
+		// A Reaper deployment is created for each data center of the replication.
+		if cfg.DatacenterAvailability == api.DatacenterAvailabilityEach && cfg.CassandraBackend.CassandraDatacenterRef == nil &&
+			cfg.CassandraBackend.Replication.NetworkTopologyStrategy == nil {
+			return EachRequiresNetworkTopology
+		}
+
+		// The cluster name and contact points are derived from the CassandraDatacenter.
+		if ref := cfg.CassandraBackend.CassandraDatacenterRef; ref != nil {
+			if ref.Name == "" {
+				return DatacenterNameRequired
+			}
+			return nil
+		}
+
+		if cfg.CassandraBackend.ClusterName == "" {
 			return ClusterNameRequired
 		}
 
@@ -45,6 +121,59 @@ func (v *validator) Validate(reaper *api.Reaper) error {
 	return nil
 }
 
+func (v *validator) ValidateUpdate(old, new *api.Reaper) error {
+	if storageType(old) != storageType(new) {
+		return StorageTypeImmutable
+	}
+
+	if storageType(new) == api.StorageTypeCassandra && keyspace(old) != keyspace(new) {
+		return KeyspaceImmutable
+	}
+
+	return nil
+}
+
+func storageType(reaper *api.Reaper) api.StorageType {
+	if reaper.Spec.ServerConfig.StorageType == "" {
+		return api.DefaultStorageType
+	}
+	return reaper.Spec.ServerConfig.StorageType
+}
+
+func keyspace(reaper *api.Reaper) string {
+	if cassandra := reaper.Spec.ServerConfig.CassandraBackend; cassandra != nil && cassandra.Keyspace != "" {
+		return cassandra.Keyspace
+	}
+	return api.DefaultKeyspace
+}
+
+func validateAutoScheduling(autoScheduling api.AutoScheduling) error {
+	periods := []string{
+		autoScheduling.InitialDelayPeriod,
+		autoScheduling.PeriodBetweenPolls,
+		autoScheduling.TimeBeforeFirstSchedule,
+		autoScheduling.ScheduleSpreadPeriod,
+	}
+	for _, period := range periods {
+		if period != "" && !isISO8601Duration(period) {
+			return InvalidAutoSchedulingPeriod
+		}
+	}
+
+	for _, keyspace := range autoScheduling.ExcludedKeyspaces {
+		if strings.TrimSpace(keyspace) == "" {
+			return ExcludedKeyspaceNameRequired
+		}
+	}
+
+	return nil
+}
+
+func isISO8601Duration(s string) bool {
+	// The regex also matches the degenerate P and PT which are not valid durations.
+	return iso8601Duration.MatchString(s) && s != "P" && !strings.HasSuffix(s, "T")
+}
+
 func (v *validator) SetDefaults(reaper *api.Reaper) bool {
 	updated := false
 	cfg := &reaper.Spec.ServerConfig
@@ -59,23 +188,106 @@ func (v *validator) SetDefaults(reaper *api.Reaper) bool {
 		updated = true
 	}
 
-	if cfg.StorageType == api.StorageTypeCassandra {
+	if reaper.Spec.Replicas == nil {
+		reaper.Spec.Replicas = int32Ptr(api.DefaultReplicas)
+		updated = true
+	}
+
+	if cfg.DatacenterAvailability == "" {
+		cfg.DatacenterAvailability = api.DefaultDatacenterAvailability
+		updated = true
+	}
+
+	if reaper.Spec.DeletionPolicy == "" {
+		reaper.Spec.DeletionPolicy = api.DefaultDeletionPolicy
+		updated = true
+	}
+
+	if cfg.HangingRepairTimeoutMins == nil {
+		cfg.HangingRepairTimeoutMins = int32Ptr(api.DefaultHangingRepairTimeoutMins)
+		updated = true
+	}
+
+	if cfg.RepairIntensity == "" {
+		cfg.RepairIntensity = api.DefaultRepairIntensity
+		updated = true
+	}
+
+	if cfg.RepairParallelism == "" {
+		cfg.RepairParallelism = api.DefaultRepairParallelism
+		updated = true
+	}
+
+	if cfg.RepairRunThreadCount == nil {
+		cfg.RepairRunThreadCount = int32Ptr(api.DefaultRepairRunThreadCount)
+		updated = true
+	}
+
+	if cfg.ScheduleDaysBetween == nil {
+		cfg.ScheduleDaysBetween = int32Ptr(api.DefaultScheduleDaysBetween)
+		updated = true
+	}
+
+	if cfg.EnableCrossOrigin == nil {
+		cfg.EnableCrossOrigin = boolPtr(api.DefaultEnableCrossOrigin)
+		updated = true
+	}
+
+	// EnableDynamicSeedList is not defaulted since its default depends on the replicas, which
+	// can change later. See newServerConfigEnvVars.
+
+	if cfg.JmxConnectionTimeoutInSeconds == nil {
+		cfg.JmxConnectionTimeoutInSeconds = int32Ptr(api.DefaultJmxConnectionTimeoutInSeconds)
+		updated = true
+	}
+
+	if cfg.SegmentCountPerNode == nil {
+		cfg.SegmentCountPerNode = int32Ptr(api.DefaultSegmentCountPerNode)
+		updated = true
+	}
+
+	if cfg.AutoScheduling.Enabled {
+		autoScheduling := &cfg.AutoScheduling
+		if autoScheduling.InitialDelayPeriod == "" {
+			autoScheduling.InitialDelayPeriod = api.DefaultAutoSchedulingInitialDelayPeriod
+			updated = true
+		}
+
+		if autoScheduling.PeriodBetweenPolls == "" {
+			autoScheduling.PeriodBetweenPolls = api.DefaultAutoSchedulingPeriodBetweenPolls
+			updated = true
+		}
+
+		if autoScheduling.TimeBeforeFirstSchedule == "" {
+			autoScheduling.TimeBeforeFirstSchedule = api.DefaultAutoSchedulingTimeBeforeFirstSchedule
+			updated = true
+		}
+
+		if autoScheduling.ScheduleSpreadPeriod == "" {
+			autoScheduling.ScheduleSpreadPeriod = api.DefaultAutoSchedulingScheduleSpreadPeriod
+			updated = true
+		}
+	}
+
+	// The backend can be missing when defaults are applied at admission before the spec is
+	// validated.
+	if cfg.StorageType == api.StorageTypeCassandra && cfg.CassandraBackend != nil {
 		cassandra := cfg.CassandraBackend
 		if cassandra.Keyspace == "" {
 			cassandra.Keyspace = api.DefaultKeyspace
 			updated = true
 		}
 
-		if cassandra.AuthProvider == (api.AuthProvider{}) {
-			cassandra.AuthProvider = api.AuthProvider{
-				Type:     "plainText",
-				Username: "cassandra",
-				Password: "cassandra",
-			}
+		// Credentials are not defaulted. Reaper connects without authentication unless a secret
+		// or, for older specs, a username and password are provided.
+		authProvider := &cassandra.AuthProvider
+		if authProvider.Type == "" && (authProvider.SecretRef.Name != "" || authProvider.Username != "") {
+			authProvider.Type = api.DefaultAuthProviderType
 			updated = true
 		}
 
-		if cassandra.Replication == (api.ReplicationConfig{}) {
+		// The replication is derived from the CassandraDatacenter when it is referenced.
+		if cassandra.Replication == (api.ReplicationConfig{}) && cassandra.CassandraDatacenterRef == nil {
 			cassandra.Replication = api.ReplicationConfig{
 				SimpleStrategy: int32Ptr(1),
 			}
@@ -89,3 +301,16 @@ func (v *validator) SetDefaults(reaper *api.Reaper) bool {
 func int32Ptr(n int32) *int32 {
 	return &n
 }
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func contains(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
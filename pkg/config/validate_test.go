@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestValidate(t *testing.T) {
@@ -68,6 +69,202 @@ func TestValidate(t *testing.T) {
 			},
 			expected: ContactPointsRequired,
 		},
+		{
+			name: "RepairIntensityNotANumber",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						RepairIntensity: "high",
+					},
+				},
+			},
+			expected: InvalidRepairIntensity,
+		},
+		{
+			name: "RepairIntensityOutOfRange",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						RepairIntensity: "1.5",
+					},
+				},
+			},
+			expected: InvalidRepairIntensity,
+		},
+		{
+			name: "InvalidRepairParallelism",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						RepairParallelism: "ALL",
+					},
+				},
+			},
+			expected: InvalidRepairParallelism,
+		},
+		{
+			name: "AutoSchedulingValidPeriods",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						AutoScheduling: api.AutoScheduling{
+							Enabled:              true,
+							InitialDelayPeriod:   "PT15S",
+							PeriodBetweenPolls:   "PT0.5S",
+							ScheduleSpreadPeriod: "P1DT6H",
+							ExcludedKeyspaces:    []string{"system_auth"},
+						},
+					},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "AutoSchedulingInvalidPeriod",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						AutoScheduling: api.AutoScheduling{
+							Enabled:            true,
+							PeriodBetweenPolls: "10m",
+						},
+					},
+				},
+			},
+			expected: InvalidAutoSchedulingPeriod,
+		},
+		{
+			name: "AutoSchedulingEmptyPeriod",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						AutoScheduling: api.AutoScheduling{
+							Enabled:                 true,
+							TimeBeforeFirstSchedule: "PT",
+						},
+					},
+				},
+			},
+			expected: InvalidAutoSchedulingPeriod,
+		},
+		{
+			name: "AutoSchedulingEmptyExcludedKeyspace",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						AutoScheduling: api.AutoScheduling{
+							Enabled:           true,
+							ExcludedKeyspaces: []string{"ks1", " "},
+						},
+					},
+				},
+			},
+			expected: ExcludedKeyspaceNameRequired,
+		},
+		{
+			name: "MemoryBackendMultipleReplicas",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					Replicas: int32Ptr(2),
+				},
+			},
+			expected: ReplicasRequireCassandra,
+		},
+		{
+			name: "TLSNoSecret",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						TLS: &api.TLSConfig{CQLEnabled: true},
+					},
+				},
+			},
+			expected: TLSSecretRequired,
+		},
+		{
+			name: "UIAuthNoSecret",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					UIAuth: &api.ReaperUIAuth{},
+				},
+			},
+			expected: UIAuthSecretRequired,
+		},
+		{
+			name: "CassandraBackendDatacenterRef",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						StorageType: api.StorageTypeCassandra,
+						CassandraBackend: &api.CassandraBackend{
+							CassandraDatacenterRef: &api.CassandraDatacenterReference{Name: "dc1"},
+						},
+					},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "CassandraBackendDatacenterRefNoName",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						StorageType: api.StorageTypeCassandra,
+						CassandraBackend: &api.CassandraBackend{
+							CassandraDatacenterRef: &api.CassandraDatacenterReference{},
+						},
+					},
+				},
+			},
+			expected: DatacenterNameRequired,
+		},
+		{
+			name: "MemoryBackendEach",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						DatacenterAvailability: api.DatacenterAvailabilityEach,
+					},
+				},
+			},
+			expected: AvailabilityRequiresCassandra,
+		},
+		{
+			name: "EachSimpleStrategy",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						StorageType:            api.StorageTypeCassandra,
+						DatacenterAvailability: api.DatacenterAvailabilityEach,
+						CassandraBackend: &api.CassandraBackend{
+							ClusterName:      "test",
+							CassandraService: "test-dc1-service",
+							Replication:      api.ReplicationConfig{SimpleStrategy: int32Ptr(3)},
+						},
+					},
+				},
+			},
+			expected: EachRequiresNetworkTopology,
+		},
+		{
+			name: "EachNetworkTopologyStrategy",
+			reaper: &api.Reaper{
+				Spec: api.ReaperSpec{
+					ServerConfig: api.ServerConfig{
+						StorageType:            api.StorageTypeCassandra,
+						DatacenterAvailability: api.DatacenterAvailabilityEach,
+						CassandraBackend: &api.CassandraBackend{
+							ClusterName:      "test",
+							CassandraService: "test-dc1-service",
+							Replication: api.ReplicationConfig{
+								NetworkTopologyStrategy: &map[string]int32{"dc1": 3, "dc2": 3},
+							},
+						},
+					},
+				},
+			},
+			expected: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -78,6 +275,62 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateUpdate(t *testing.T) {
+	validator := NewValidator()
+	cassandraReaper := func(keyspace string) *api.Reaper {
+		return &api.Reaper{
+			Spec: api.ReaperSpec{
+				ServerConfig: api.ServerConfig{
+					StorageType: api.StorageTypeCassandra,
+					CassandraBackend: &api.CassandraBackend{
+						ClusterName:      "test",
+						CassandraService: "test-svc",
+						Keyspace:         keyspace,
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name     string
+		old      *api.Reaper
+		new      *api.Reaper
+		expected error
+	}{
+		{
+			name:     "StorageTypeDefaulted",
+			old:      &api.Reaper{},
+			new:      &api.Reaper{Spec: api.ReaperSpec{ServerConfig: api.ServerConfig{StorageType: api.StorageTypeMemory}}},
+			expected: nil,
+		},
+		{
+			name:     "StorageTypeChanged",
+			old:      &api.Reaper{Spec: api.ReaperSpec{ServerConfig: api.ServerConfig{StorageType: api.StorageTypeMemory}}},
+			new:      cassandraReaper(""),
+			expected: StorageTypeImmutable,
+		},
+		{
+			name:     "KeyspaceDefaulted",
+			old:      cassandraReaper(""),
+			new:      cassandraReaper(api.DefaultKeyspace),
+			expected: nil,
+		},
+		{
+			name:     "KeyspaceChanged",
+			old:      cassandraReaper("reaper_db"),
+			new:      cassandraReaper("reaper"),
+			expected: KeyspaceImmutable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validator.ValidateUpdate(tt.old, tt.new); got != tt.expected {
+				t.Errorf("expected (%s), got (%s)", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestSetDefaults(t *testing.T) {
 	validator := NewValidator()
 	reaper := &api.Reaper{}
@@ -91,6 +344,30 @@ func TestSetDefaults(t *testing.T) {
 	if cfg.StorageType != api.DefaultStorageType {
 		t.Errorf("StorageType (%s) is not the expected value (%s)", cfg.StorageType, api.DefaultStorageType)
 	}
+
+	if cfg.RepairIntensity != api.DefaultRepairIntensity {
+		t.Errorf("RepairIntensity (%s) is not the expected value (%s)", cfg.RepairIntensity, api.DefaultRepairIntensity)
+	}
+
+	if *cfg.SegmentCountPerNode != api.DefaultSegmentCountPerNode {
+		t.Errorf("SegmentCountPerNode (%d) is not the expected value (%d)", *cfg.SegmentCountPerNode, api.DefaultSegmentCountPerNode)
+	}
+
+	if *reaper.Spec.Replicas != api.DefaultReplicas {
+		t.Errorf("Replicas (%d) is not the expected value (%d)", *reaper.Spec.Replicas, api.DefaultReplicas)
+	}
+
+	if cfg.DatacenterAvailability != api.DefaultDatacenterAvailability {
+		t.Errorf("DatacenterAvailability (%s) is not the expected value (%s)", cfg.DatacenterAvailability, api.DefaultDatacenterAvailability)
+	}
+
+	if reaper.Spec.DeletionPolicy != api.DefaultDeletionPolicy {
+		t.Errorf("DeletionPolicy (%s) is not the expected value (%s)", reaper.Spec.DeletionPolicy, api.DefaultDeletionPolicy)
+	}
+
+	if updated := validator.SetDefaults(reaper); updated {
+		t.Errorf("Expected ServerConfig to not get updated when defaults are already set")
+	}
 }
 
 func TestSetDefaultsWithCassandraBackend(t *testing.T) {
@@ -116,13 +393,9 @@ func TestSetDefaultsWithCassandraBackend(t *testing.T) {
 		t.Errorf("Keyspace (%s) is not the expectedAuthProvider value (%s)", (*cfg.CassandraBackend).Keyspace, api.DefaultKeyspace)
 	}
 
-	expectedAuthProvider := api.AuthProvider{
-		Type:     "plainText",
-		Username: "cassandra",
-		Password: "cassandra",
-	}
-	if (*cfg.CassandraBackend).AuthProvider != expectedAuthProvider {
-		t.Errorf("AuthProvider (%+v) is not the expectedAuthProvider value (%+v)", (*cfg.CassandraBackend).AuthProvider, expectedAuthProvider)
+	// Credentials must not be defaulted into the spec.
+	if (*cfg.CassandraBackend).AuthProvider != (api.AuthProvider{}) {
+		t.Errorf("AuthProvider (%+v) should not be set", (*cfg.CassandraBackend).AuthProvider)
 	}
 
 	if *cfg.CassandraBackend.Replication.SimpleStrategy != 1 {
@@ -133,3 +406,83 @@ func TestSetDefaultsWithCassandraBackend(t *testing.T) {
 		t.Errorf("NetworkTopologyStrategy (%+v) should be nil", *cfg.CassandraBackend.Replication.NetworkTopologyStrategy)
 	}
 }
+
+func TestSetDefaultsWithAuthSecret(t *testing.T) {
+	validator := NewValidator()
+	reaper := &api.Reaper{
+		Spec: api.ReaperSpec{
+			ServerConfig: api.ServerConfig{
+				StorageType: api.StorageTypeCassandra,
+				CassandraBackend: &api.CassandraBackend{
+					ClusterName: "test",
+					AuthProvider: api.AuthProvider{
+						SecretRef: corev1.LocalObjectReference{Name: "reaper-cql"},
+					},
+				},
+			},
+		},
+	}
+
+	validator.SetDefaults(reaper)
+
+	expectedAuthProvider := api.AuthProvider{
+		Type:      api.DefaultAuthProviderType,
+		SecretRef: corev1.LocalObjectReference{Name: "reaper-cql"},
+	}
+	if authProvider := reaper.Spec.ServerConfig.CassandraBackend.AuthProvider; authProvider != expectedAuthProvider {
+		t.Errorf("AuthProvider (%+v) is not the expected value (%+v)", authProvider, expectedAuthProvider)
+	}
+}
+
+func TestSetDefaultsWithMultipleReplicas(t *testing.T) {
+	validator := NewValidator()
+	reaper := &api.Reaper{
+		Spec: api.ReaperSpec{
+			Replicas: int32Ptr(3),
+			ServerConfig: api.ServerConfig{
+				StorageType: api.StorageTypeCassandra,
+				CassandraBackend: &api.CassandraBackend{
+					ClusterName: "test",
+				},
+			},
+		},
+	}
+
+	validator.SetDefaults(reaper)
+
+	// The default is derived from the replicas when the deployment is created so that it
+	// follows scaling.
+	if reaper.Spec.ServerConfig.EnableDynamicSeedList != nil {
+		t.Errorf("EnableDynamicSeedList should not be defaulted, got (%t)", *reaper.Spec.ServerConfig.EnableDynamicSeedList)
+	}
+}
+
+func TestSetDefaultsWithAutoScheduling(t *testing.T) {
+	validator := NewValidator()
+	reaper := &api.Reaper{
+		Spec: api.ReaperSpec{
+			ServerConfig: api.ServerConfig{
+				AutoScheduling: api.AutoScheduling{
+					Enabled:            true,
+					PeriodBetweenPolls: "PT1M",
+				},
+			},
+		},
+	}
+
+	validator.SetDefaults(reaper)
+
+	autoScheduling := reaper.Spec.ServerConfig.AutoScheduling
+
+	if autoScheduling.InitialDelayPeriod != api.DefaultAutoSchedulingInitialDelayPeriod {
+		t.Errorf("InitialDelayPeriod (%s) is not the expected value (%s)", autoScheduling.InitialDelayPeriod, api.DefaultAutoSchedulingInitialDelayPeriod)
+	}
+
+	if autoScheduling.PeriodBetweenPolls != "PT1M" {
+		t.Errorf("PeriodBetweenPolls (%s) should not have been changed", autoScheduling.PeriodBetweenPolls)
+	}
+
+	if autoScheduling.ScheduleSpreadPeriod != api.DefaultAutoSchedulingScheduleSpreadPeriod {
+		t.Errorf("ScheduleSpreadPeriod (%s) is not the expected value (%s)", autoScheduling.ScheduleSpreadPeriod, api.DefaultAutoSchedulingScheduleSpreadPeriod)
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WatchNamespaceEnvVar is the env var that specifies the namespaces that the operator watches.
+// The value is either a single namespace, a comma-separated list of namespaces, or empty to
+// watch all namespaces.
+const WatchNamespaceEnvVar = "WATCH_NAMESPACE"
+
+// WatchNamespaces are the namespaces that the operator watches. It is empty if the operator
+// watches all namespaces.
+type WatchNamespaces []string
+
+// Returns the namespaces from WatchNamespaceEnvVar. An error is returned if it is not set, in
+// which case all namespaces are watched.
+func GetWatchNamespaces() (WatchNamespaces, error) {
+	value, found := os.LookupEnv(WatchNamespaceEnvVar)
+	if !found {
+		return nil, fmt.Errorf("%s must be set", WatchNamespaceEnvVar)
+	}
+	return ParseWatchNamespaces(value), nil
+}
+
+// Parses a comma-separated list of namespaces. Blank and duplicate entries are ignored, so an
+// empty value means all namespaces.
+func ParseWatchNamespaces(value string) WatchNamespaces {
+	namespaces := WatchNamespaces{}
+	seen := make(map[string]bool)
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if len(namespace) == 0 || seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
+
+func (w WatchNamespaces) AllNamespaces() bool {
+	return len(w) == 0
+}
+
+// Returns true if objects in the namespace are visible to the operator.
+func (w WatchNamespaces) Contains(namespace string) bool {
+	if w.AllNamespaces() {
+		return true
+	}
+	for _, ns := range w {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns an error if the object in the namespace is not visible to the operator. kind and key
+// describe the object in the error.
+func (w WatchNamespaces) Check(kind, key, namespace string) error {
+	if w.Contains(namespace) {
+		return nil
+	}
+	return fmt.Errorf("%s %s is in namespace %s which is not watched by the operator, add it to %s", kind, key, namespace, WatchNamespaceEnvVar)
+}
+
+func (w WatchNamespaces) String() string {
+	if w.AllNamespaces() {
+		return "all namespaces"
+	}
+	return strings.Join(w, ",")
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWatchNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected WatchNamespaces
+	}{
+		{name: "AllNamespaces", value: "", expected: WatchNamespaces{}},
+		{name: "SingleNamespace", value: "reaper-operator", expected: WatchNamespaces{"reaper-operator"}},
+		{name: "MultipleNamespaces", value: "platform, team-a,team-b", expected: WatchNamespaces{"platform", "team-a", "team-b"}},
+		{name: "BlankAndDuplicateEntries", value: "platform,,team-a, platform ,", expected: WatchNamespaces{"platform", "team-a"}},
+		{name: "OnlySeparators", value: " , ", expected: WatchNamespaces{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := ParseWatchNamespaces(tt.value); !reflect.DeepEqual(tt.expected, actual) {
+				t.Errorf("expected (%v), got (%v)", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWatchNamespacesContains(t *testing.T) {
+	all := ParseWatchNamespaces("")
+	if !all.AllNamespaces() || !all.Contains("team-a") {
+		t.Errorf("expected all namespaces to be watched")
+	}
+
+	some := ParseWatchNamespaces("platform,team-a")
+	if some.AllNamespaces() {
+		t.Errorf("expected only (%s) to be watched", some)
+	}
+	if !some.Contains("team-a") {
+		t.Errorf("expected (team-a) to be watched")
+	}
+	if some.Contains("team-b") {
+		t.Errorf("expected (team-b) not to be watched")
+	}
+	if err := some.Check("reaper", "team-b/reaper", "team-b"); err == nil {
+		t.Errorf("expected an error for a reaper in (team-b)")
+	}
+}
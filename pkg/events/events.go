@@ -0,0 +1,28 @@
+package events
+
+// The reasons of the events that the operator records on Reapers and CassandraDatacenters.
+const (
+	CreatedReason      = "Created"
+	CreateFailedReason = "CreateFailed"
+	UpdatedReason      = "Updated"
+	UpdateFailedReason = "UpdateFailed"
+	DeletedReason      = "Deleted"
+	DeleteFailedReason = "DeleteFailed"
+
+	InvalidConfigReason      = "InvalidConfig"
+	BackendUnavailableReason = "CassandraBackendUnavailable"
+	SecretErrorReason        = "SecretError"
+
+	SchemaAppliedReason      = "SchemaApplied"
+	SchemaJobFailedReason    = "SchemaJobFailed"
+	KeyspaceDroppedReason    = "KeyspaceDropped"
+	KeyspaceDropFailedReason = "KeyspaceDropFailed"
+
+	ClusterRegisteredReason           = "ClusterRegistered"
+	ClusterRegistrationFailedReason   = "ClusterRegistrationFailed"
+	ClusterUnregisteredReason         = "ClusterUnregistered"
+	ClusterUnregistrationFailedReason = "ClusterUnregistrationFailed"
+
+	SidecarInjectedReason = "SidecarInjected"
+	SidecarRemovedReason  = "SidecarRemoved"
+)
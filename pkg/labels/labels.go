@@ -4,6 +4,9 @@ const (
 	ManagedByLabel      = "app.kubernetes.io/managed-by"
 	ManagedByLabelValue = "reaper-operator"
 	ReaperLabel         = "reaper.cassandra-reaper.io/reaper"
+
+	// The data center of the Reaper instance when a deployment runs per data center.
+	DatacenterLabel = "reaper.cassandra-reaper.io/datacenter"
 )
 
 func SetOperatorLabels(m map[string]string) {
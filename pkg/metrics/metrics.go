@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "reaper_operator"
+
+var (
+	registeredClusters = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "registered_clusters",
+		Help:      "The number of clusters that are registered with the Reaper.",
+	}, []string{"namespace", "reaper"})
+
+	reaperReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "reaper_ready",
+		Help:      "Whether the Reaper is ready (1) or not (0).",
+	}, []string{"namespace", "reaper"})
+
+	clusterRegistrationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "cluster_registration_failures_total",
+		Help:      "The number of times that registering a cluster or updating its seeds with the Reaper failed.",
+	}, []string{"namespace", "reaper", "cluster"})
+
+	schemaJobAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "schema_job_attempts_total",
+		Help:      "The number of schema jobs that were created for the Reaper.",
+	}, []string{"namespace", "reaper", "job"})
+
+	restRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "rest_request_duration_seconds",
+		Help:      "The duration of the requests to Reaper's REST API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint"})
+
+	restRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "rest_request_errors_total",
+		Help:      "The number of requests to Reaper's REST API that failed.",
+	}, []string{"method", "endpoint"})
+
+	runningRepairs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "running_repairs",
+		Help:      "The number of repair runs of the cluster that are running.",
+	}, []string{"namespace", "reaper", "cluster"})
+
+	repairSchedules = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "repair_schedules",
+		Help:      "The number of repair schedules of the cluster.",
+	}, []string{"namespace", "reaper", "cluster"})
+
+	secondsSinceLastSuccessfulRepair = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "seconds_since_last_successful_repair",
+		Help:      "The time since the last repair run of the cluster that completed successfully.",
+	}, []string{"namespace", "reaper", "cluster"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		registeredClusters,
+		reaperReady,
+		clusterRegistrationFailures,
+		schemaJobAttempts,
+		restRequestDuration,
+		restRequestErrors,
+		runningRepairs,
+		repairSchedules,
+		secondsSinceLastSuccessfulRepair,
+	)
+}
+
+// ClusterKey identifies the metrics of a cluster that is registered with a Reaper.
+type ClusterKey struct {
+	Namespace string
+
+	Reaper string
+
+	Cluster string
+}
+
+// ClusterRepairs is the repair state of a cluster as polled from Reaper.
+type ClusterRepairs struct {
+	RunningRepairs int
+
+	RepairSchedules int
+
+	// The end time of the last repair run that completed successfully. It is zero if there is
+	// no such run.
+	LastSuccessfulRepair time.Time
+}
+
+// Sets the metrics that are derived from the Reaper's status.
+func SetReaperStatus(reaper *api.Reaper) {
+	registeredClusters.WithLabelValues(reaper.Namespace, reaper.Name).Set(float64(len(reaper.Status.Clusters)))
+
+	ready := 0.0
+	if reaper.Status.Ready {
+		ready = 1.0
+	}
+	reaperReady.WithLabelValues(reaper.Namespace, reaper.Name).Set(ready)
+}
+
+// Deletes the metrics that are derived from the status of the Reaper after it is deleted.
+func DeleteReaperStatus(namespace, name string) {
+	registeredClusters.DeleteLabelValues(namespace, name)
+	reaperReady.DeleteLabelValues(namespace, name)
+}
+
+func IncClusterRegistrationFailures(key ClusterKey) {
+	clusterRegistrationFailures.WithLabelValues(key.Namespace, key.Reaper, key.Cluster).Inc()
+}
+
+func IncSchemaJobAttempts(namespace, reaper, job string) {
+	schemaJobAttempts.WithLabelValues(namespace, reaper, job).Inc()
+}
+
+// Records the duration of a request to Reaper's REST API and whether it failed. The endpoint
+// should not contain ids or names so that the number of series stays bounded.
+func ObserveRESTRequest(method, endpoint string, duration time.Duration, failed bool) {
+	restRequestDuration.WithLabelValues(method, endpoint).Observe(duration.Seconds())
+	if failed {
+		restRequestErrors.WithLabelValues(method, endpoint).Inc()
+	}
+}
+
+// Sets the repair metrics of the cluster. The time since the last successful repair is relative
+// to now and is not set if the cluster has not been repaired successfully yet.
+func SetClusterRepairs(key ClusterKey, repairs ClusterRepairs, now time.Time) {
+	runningRepairs.WithLabelValues(key.Namespace, key.Reaper, key.Cluster).Set(float64(repairs.RunningRepairs))
+	repairSchedules.WithLabelValues(key.Namespace, key.Reaper, key.Cluster).Set(float64(repairs.RepairSchedules))
+
+	if repairs.LastSuccessfulRepair.IsZero() {
+		secondsSinceLastSuccessfulRepair.DeleteLabelValues(key.Namespace, key.Reaper, key.Cluster)
+	} else {
+		secondsSinceLastSuccessfulRepair.WithLabelValues(key.Namespace, key.Reaper, key.Cluster).Set(now.Sub(repairs.LastSuccessfulRepair).Seconds())
+	}
+}
+
+// Deletes the repair metrics of a cluster that is no longer registered with the Reaper.
+func DeleteClusterRepairs(key ClusterKey) {
+	runningRepairs.DeleteLabelValues(key.Namespace, key.Reaper, key.Cluster)
+	repairSchedules.DeleteLabelValues(key.Namespace, key.Reaper, key.Cluster)
+	secondsSinceLastSuccessfulRepair.DeleteLabelValues(key.Namespace, key.Reaper, key.Cluster)
+}
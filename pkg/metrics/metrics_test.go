@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetReaperStatus(t *testing.T) {
+	reaper := &api.Reaper{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "reaper"},
+		Status:     api.ReaperStatus{Ready: true, Clusters: []string{"c1", "c2"}},
+	}
+
+	SetReaperStatus(reaper)
+
+	if clusters := testutil.ToFloat64(registeredClusters.WithLabelValues("test", "reaper")); clusters != 2 {
+		t.Errorf("expected 2 registered clusters, got (%f)", clusters)
+	}
+	if ready := testutil.ToFloat64(reaperReady.WithLabelValues("test", "reaper")); ready != 1 {
+		t.Errorf("expected reaper to be ready, got (%f)", ready)
+	}
+
+	DeleteReaperStatus("test", "reaper")
+
+	if count := testutil.CollectAndCount(registeredClusters); count != 0 {
+		t.Errorf("expected registered clusters to be deleted, got (%d) series", count)
+	}
+}
+
+func TestSetClusterRepairs(t *testing.T) {
+	key := ClusterKey{Namespace: "test", Reaper: "reaper", Cluster: "c1"}
+	now := time.Date(2020, 11, 2, 12, 0, 0, 0, time.UTC)
+
+	SetClusterRepairs(key, ClusterRepairs{RunningRepairs: 1, RepairSchedules: 3, LastSuccessfulRepair: now.Add(-time.Hour)}, now)
+
+	if running := testutil.ToFloat64(runningRepairs.WithLabelValues("test", "reaper", "c1")); running != 1 {
+		t.Errorf("expected 1 running repair, got (%f)", running)
+	}
+	if schedules := testutil.ToFloat64(repairSchedules.WithLabelValues("test", "reaper", "c1")); schedules != 3 {
+		t.Errorf("expected 3 repair schedules, got (%f)", schedules)
+	}
+	if seconds := testutil.ToFloat64(secondsSinceLastSuccessfulRepair.WithLabelValues("test", "reaper", "c1")); seconds != 3600 {
+		t.Errorf("expected 3600 seconds since last successful repair, got (%f)", seconds)
+	}
+
+	SetClusterRepairs(key, ClusterRepairs{}, now)
+
+	if count := testutil.CollectAndCount(secondsSinceLastSuccessfulRepair); count != 0 {
+		t.Errorf("expected time since last successful repair to be deleted, got (%d) series", count)
+	}
+
+	DeleteClusterRepairs(key)
+
+	if count := testutil.CollectAndCount(runningRepairs); count != 0 {
+		t.Errorf("expected running repairs to be deleted, got (%d) series", count)
+	}
+}
@@ -0,0 +1,478 @@
+package reaperclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	reapergo "github.com/jsanda/reaper-client-go/reaper"
+	"github.com/thelastpickle/reaper-operator/pkg/metrics"
+)
+
+// The timeout of a request to Reaper, including reading the response. It bounds how long a
+// reconciliation waits for a Reaper that is unresponsive.
+const requestTimeout = 30 * time.Second
+
+var (
+	RepairScheduleNotFound = errors.New("repair schedule not found")
+
+	RepairRunNotFound = errors.New("repair run not found")
+)
+
+// Client implements reaper-client-go's client interface and adds the repair endpoints of
+// Reaper's REST API which are not yet supported there. The cluster endpoints are implemented
+// here as well since reaper-client-go cannot authenticate with Reaper, see clusters.go.
+type Client interface {
+	reapergo.ReaperClient
+
+	CreateRepairSchedule(ctx context.Context, options RepairScheduleOptions) (*RepairSchedule, error)
+
+	GetRepairSchedule(ctx context.Context, id string) (*RepairSchedule, error)
+
+	// Pauses the repair schedule. Reaper only deletes schedules that are paused.
+	PauseRepairSchedule(ctx context.Context, id string) error
+
+	// Deletes the paused repair schedule. owner has to be the owner that the schedule was
+	// created with.
+	DeleteRepairSchedule(ctx context.Context, id, owner string) error
+
+	GetRepairSchedules(ctx context.Context, cluster string) ([]RepairSchedule, error)
+
+	CreateRepairRun(ctx context.Context, options RepairOptions) (*RepairRun, error)
+
+	GetRepairRun(ctx context.Context, id string) (*RepairRun, error)
+
+	// Returns the repair runs of the cluster that are in one of the states, or all of its
+	// runs if no state is given.
+	GetRepairRuns(ctx context.Context, cluster string, states ...string) ([]RepairRun, error)
+
+	// Changes the state of the repair run. Reaper accepts RUNNING, PAUSED and ABORTED. The
+	// current run is returned if it is already in the state.
+	UpdateRepairRunState(ctx context.Context, id, state string) (*RepairRun, error)
+}
+
+// RepairOptions are the parameters shared by repair schedules and repair runs.
+type RepairOptions struct {
+	ClusterName string
+
+	Keyspace string
+
+	Owner string
+
+	Tables []string
+
+	Intensity string
+
+	RepairParallelism string
+
+	SegmentCountPerNode *int32
+
+	IncrementalRepair bool
+}
+
+type RepairScheduleOptions struct {
+	RepairOptions
+
+	ScheduleDaysBetween *int32
+
+	ScheduleTriggerTime string
+}
+
+type RepairSchedule struct {
+	Id string `json:"id"`
+
+	Owner string `json:"owner"`
+
+	State string `json:"state"`
+
+	ClusterName string `json:"cluster_name"`
+
+	KeyspaceName string `json:"keyspace_name"`
+
+	ColumnFamilies []string `json:"column_families"`
+
+	NextActivation string `json:"next_activation"`
+
+	DaysBetween int32 `json:"scheduled_days_between"`
+
+	Intensity float64 `json:"intensity"`
+
+	RepairParallelism string `json:"repair_parallelism"`
+
+	SegmentCountPerNode int32 `json:"segment_count_per_node"`
+
+	IncrementalRepair bool `json:"incremental_repair"`
+}
+
+type RepairRun struct {
+	Id string `json:"id"`
+
+	Owner string `json:"owner"`
+
+	State string `json:"state"`
+
+	ClusterName string `json:"cluster_name"`
+
+	KeyspaceName string `json:"keyspace_name"`
+
+	ColumnFamilies []string `json:"column_families"`
+
+	CreationTime string `json:"creation_time"`
+
+	StartTime string `json:"start_time"`
+
+	EndTime string `json:"end_time"`
+
+	SegmentsRepaired int32 `json:"segments_repaired"`
+
+	TotalSegments int32 `json:"total_segments"`
+
+	LastEvent string `json:"last_event"`
+}
+
+// Credentials are the username and password with which the client logs in to Reaper when
+// Reaper's authentication is enabled.
+type Credentials struct {
+	Username string
+
+	Password string
+}
+
+type client struct {
+	baseURL *url.URL
+
+	httpClient *http.Client
+
+	credentials *Credentials
+
+	// Guards loggedIn. The session cookie itself is kept in the http client's cookie jar.
+	mu sync.Mutex
+
+	loggedIn bool
+}
+
+func NewClient(baseURL string) (Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client{baseURL: u, httpClient: &http.Client{Timeout: requestTimeout}}, nil
+}
+
+// NewAuthenticatedClient creates a client that logs in to Reaper with the credentials before
+// its first request and again whenever Reaper reports that the session is no longer valid.
+func NewAuthenticatedClient(baseURL string, credentials Credentials) (Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Jar:     jar,
+		Timeout: requestTimeout,
+		// Reaper redirects unauthenticated requests to its login page. The redirect is not
+		// followed so that it can be detected.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &client{baseURL: u, httpClient: httpClient, credentials: &credentials}, nil
+}
+
+func (c *client) CreateRepairSchedule(ctx context.Context, options RepairScheduleOptions) (*RepairSchedule, error) {
+	params := options.RepairOptions.toQuery()
+	if options.ScheduleDaysBetween != nil {
+		params.Set("scheduleDaysBetween", strconv.FormatInt(int64(*options.ScheduleDaysBetween), 10))
+	}
+	if len(options.ScheduleTriggerTime) > 0 {
+		params.Set("scheduleTriggerTime", options.ScheduleTriggerTime)
+	}
+
+	schedule := &RepairSchedule{}
+	if err := c.doRequest(ctx, http.MethodPost, "/repair_schedule", params, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create repair schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (c *client) GetRepairSchedule(ctx context.Context, id string) (*RepairSchedule, error) {
+	schedule := &RepairSchedule{}
+	if err := c.doRequest(ctx, http.MethodGet, "/repair_schedule/"+id, nil, schedule); err != nil {
+		if err == errNotFound {
+			return nil, RepairScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to get repair schedule (%s): %w", id, err)
+	}
+
+	return schedule, nil
+}
+
+func (c *client) PauseRepairSchedule(ctx context.Context, id string) error {
+	params := url.Values{}
+	params.Set("state", "PAUSED")
+
+	if err := c.doRequest(ctx, http.MethodPut, "/repair_schedule/"+id, params, nil); err != nil {
+		switch err {
+		case errNotFound:
+			return RepairScheduleNotFound
+		case errNotModified:
+			// The schedule is already paused.
+			return nil
+		}
+		return fmt.Errorf("failed to pause repair schedule (%s): %w", id, err)
+	}
+
+	return nil
+}
+
+func (c *client) DeleteRepairSchedule(ctx context.Context, id, owner string) error {
+	params := url.Values{}
+	params.Set("owner", owner)
+
+	if err := c.doRequest(ctx, http.MethodDelete, "/repair_schedule/"+id, params, nil); err != nil {
+		if err == errNotFound {
+			return RepairScheduleNotFound
+		}
+		return fmt.Errorf("failed to delete repair schedule (%s): %w", id, err)
+	}
+
+	return nil
+}
+
+func (c *client) GetRepairSchedules(ctx context.Context, cluster string) ([]RepairSchedule, error) {
+	params := url.Values{}
+	params.Set("clusterName", cluster)
+
+	schedules := []RepairSchedule{}
+	if err := c.doRequest(ctx, http.MethodGet, "/repair_schedule", params, &schedules); err != nil {
+		return nil, fmt.Errorf("failed to get repair schedules of cluster (%s): %w", cluster, err)
+	}
+
+	return schedules, nil
+}
+
+func (c *client) CreateRepairRun(ctx context.Context, options RepairOptions) (*RepairRun, error) {
+	run := &RepairRun{}
+	if err := c.doRequest(ctx, http.MethodPost, "/repair_run", options.toQuery(), run); err != nil {
+		return nil, fmt.Errorf("failed to create repair run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (c *client) GetRepairRun(ctx context.Context, id string) (*RepairRun, error) {
+	run := &RepairRun{}
+	if err := c.doRequest(ctx, http.MethodGet, "/repair_run/"+id, nil, run); err != nil {
+		if err == errNotFound {
+			return nil, RepairRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get repair run (%s): %w", id, err)
+	}
+
+	return run, nil
+}
+
+func (c *client) GetRepairRuns(ctx context.Context, cluster string, states ...string) ([]RepairRun, error) {
+	params := url.Values{}
+	params.Set("cluster_name", cluster)
+	if len(states) > 0 {
+		params.Set("state", strings.Join(states, ","))
+	}
+
+	runs := []RepairRun{}
+	if err := c.doRequest(ctx, http.MethodGet, "/repair_run", params, &runs); err != nil {
+		return nil, fmt.Errorf("failed to get repair runs of cluster (%s): %w", cluster, err)
+	}
+
+	return runs, nil
+}
+
+func (c *client) UpdateRepairRunState(ctx context.Context, id, state string) (*RepairRun, error) {
+	run := &RepairRun{}
+	if err := c.doRequest(ctx, http.MethodPut, fmt.Sprintf("/repair_run/%s/state/%s", id, state), nil, run); err != nil {
+		switch err {
+		case errNotFound:
+			return nil, RepairRunNotFound
+		case errNotModified:
+			// Reaper does not return the run if its state did not change.
+			return c.GetRepairRun(ctx, id)
+		}
+		return nil, fmt.Errorf("failed to set state of repair run (%s) to %s: %w", id, state, err)
+	}
+
+	return run, nil
+}
+
+func (o RepairOptions) toQuery() url.Values {
+	params := url.Values{}
+	params.Set("clusterName", o.ClusterName)
+	params.Set("keyspace", o.Keyspace)
+	params.Set("owner", o.Owner)
+
+	if len(o.Tables) > 0 {
+		params.Set("tables", strings.Join(o.Tables, ","))
+	}
+	if len(o.Intensity) > 0 {
+		params.Set("intensity", o.Intensity)
+	}
+	if len(o.RepairParallelism) > 0 {
+		params.Set("repairParallelism", o.RepairParallelism)
+	}
+	if o.SegmentCountPerNode != nil {
+		params.Set("segmentCountPerNode", strconv.FormatInt(int64(*o.SegmentCountPerNode), 10))
+	}
+	params.Set("incrementalRepair", strconv.FormatBool(o.IncrementalRepair))
+
+	return params
+}
+
+var (
+	errNotFound = errors.New("not found")
+
+	errNotModified = errors.New("not modified")
+)
+
+// Sends the request and decodes the JSON response body into v if v is not nil. errNotFound is
+// returned for a 404 and errNotModified for a 304, which Reaper returns when a state change is
+// a no-op, so that callers can map them to more specific errors. If the client has
+// credentials, it logs in first and retries once when the session has expired. The duration of
+// the request and whether it failed are recorded in the metrics. A 404 is not counted as a
+// failure since it is how Reaper reports that a cluster, schedule or run does not exist.
+func (c *client) doRequest(ctx context.Context, method, path string, params url.Values, v interface{}) error {
+	start := time.Now()
+	err := c.doAuthenticatedRequest(ctx, method, path, params, v)
+	metrics.ObserveRESTRequest(method, getEndpoint(path), time.Since(start), err != nil && err != errNotFound && err != errNotModified)
+
+	return err
+}
+
+func (c *client) doAuthenticatedRequest(ctx context.Context, method, path string, params url.Values, v interface{}) error {
+	if err := c.ensureLoggedIn(ctx); err != nil {
+		return err
+	}
+
+	resp, err := c.send(ctx, method, path, params)
+	if err != nil {
+		return err
+	}
+
+	if c.credentials != nil && isUnauthenticated(resp) {
+		resp.Body.Close()
+		c.setLoggedIn(false)
+		if err = c.ensureLoggedIn(ctx); err != nil {
+			return err
+		}
+		if resp, err = c.send(ctx, method, path, params); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case resp.StatusCode == http.StatusNotModified:
+		return errNotModified
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return reapergo.ErrRedirectsNotSupported
+	case resp.StatusCode >= 400:
+		if body, err := ioutil.ReadAll(resp.Body); err == nil {
+			return fmt.Errorf("request failed: msg (%s), status code (%d)", string(body), resp.StatusCode)
+		}
+		return fmt.Errorf("request failed: status code (%d)", resp.StatusCode)
+	}
+
+	if v != nil {
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+
+	return nil
+}
+
+// Returns the resource of the path, e.g., /repair_run for /repair_run/123/state/RUNNING, so
+// that the endpoints in the metrics do not contain ids or names.
+func getEndpoint(path string) string {
+	return "/" + strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+}
+
+func (c *client) send(ctx context.Context, method, path string, params url.Values) (*http.Response, error) {
+	u := c.baseURL.ResolveReference(&url.URL{Path: path})
+	if params != nil {
+		u.RawQuery = params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	return c.httpClient.Do(req)
+}
+
+func (c *client) ensureLoggedIn(ctx context.Context) error {
+	if c.credentials == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loggedIn {
+		return nil
+	}
+
+	form := url.Values{}
+	form.Set("username", c.credentials.Username)
+	form.Set("password", c.credentials.Password)
+	form.Set("rememberMe", "false")
+
+	u := c.baseURL.ResolveReference(&url.URL{Path: "/login"})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to log in to reaper: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("failed to log in to reaper: status code (%d)", resp.StatusCode)
+	}
+	c.loggedIn = true
+
+	return nil
+}
+
+func (c *client) setLoggedIn(loggedIn bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loggedIn = loggedIn
+}
+
+// Returns true if Reaper rejected the request or redirected it to the login page because
+// the request does not have a valid session. A 304 is not a redirect.
+func isUnauthenticated(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusUnauthorized || (resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.StatusCode != http.StatusNotModified)
+}
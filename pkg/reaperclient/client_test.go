@@ -0,0 +1,345 @@
+package reaperclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	reapergo "github.com/jsanda/reaper-client-go/reaper"
+)
+
+func TestCreateRepairSchedule(t *testing.T) {
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repair_schedule" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		query = map[string]string{}
+		for k := range r.URL.Query() {
+			query[k] = r.URL.Query().Get(k)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(RepairSchedule{Id: "123", State: "ACTIVE", NextActivation: "2020-11-01T02:00:00Z"})
+	}))
+	defer server.Close()
+
+	restClient, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	segments := int32(16)
+	days := int32(7)
+	schedule, err := restClient.CreateRepairSchedule(context.Background(), RepairScheduleOptions{
+		RepairOptions: RepairOptions{
+			ClusterName:         "test",
+			Keyspace:            "ks",
+			Owner:               "tester",
+			Tables:              []string{"t1", "t2"},
+			SegmentCountPerNode: &segments,
+		},
+		ScheduleDaysBetween: &days,
+	})
+	if err != nil {
+		t.Fatalf("failed to create repair schedule: %s", err)
+	}
+
+	if schedule.Id != "123" || schedule.State != "ACTIVE" {
+		t.Errorf("unexpected schedule: %+v", schedule)
+	}
+
+	expected := map[string]string{
+		"clusterName":         "test",
+		"keyspace":            "ks",
+		"owner":               "tester",
+		"tables":              "t1,t2",
+		"segmentCountPerNode": "16",
+		"scheduleDaysBetween": "7",
+		"incrementalRepair":   "false",
+	}
+	for k, v := range expected {
+		if query[k] != v {
+			t.Errorf("expected query param %s=%s, got (%s)", k, v, query[k])
+		}
+	}
+	if _, found := query["intensity"]; found {
+		t.Errorf("intensity should not be set")
+	}
+}
+
+func TestGetRepairScheduleNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	restClient, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	if _, err := restClient.GetRepairSchedule(context.Background(), "123"); err != RepairScheduleNotFound {
+		t.Errorf("expected (%s), got (%s)", RepairScheduleNotFound, err)
+	}
+
+	if err := restClient.DeleteRepairSchedule(context.Background(), "123", "tester"); err != RepairScheduleNotFound {
+		t.Errorf("expected (%s), got (%s)", RepairScheduleNotFound, err)
+	}
+}
+
+func TestPauseRepairSchedule(t *testing.T) {
+	state := "ACTIVE"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			return
+		}
+		if r.Method != http.MethodPut || r.URL.Path != "/repair_schedule/123" || r.URL.Query().Get("state") != "PAUSED" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+		if state == "PAUSED" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		state = "PAUSED"
+		_ = json.NewEncoder(w).Encode(RepairSchedule{Id: "123", State: state})
+	}))
+	defer server.Close()
+
+	restClient, err := NewAuthenticatedClient(server.URL, Credentials{Username: "admin", Password: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	if err := restClient.PauseRepairSchedule(context.Background(), "123"); err != nil {
+		t.Fatalf("failed to pause repair schedule: %s", err)
+	}
+	// Reaper responds with 304 if the schedule is already paused.
+	if err := restClient.PauseRepairSchedule(context.Background(), "123"); err != nil {
+		t.Errorf("failed to pause repair schedule that is already paused: %s", err)
+	}
+}
+
+func TestUpdateRepairRunState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/repair_run/123/state/RUNNING" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(RepairRun{Id: "123", State: "RUNNING", SegmentsRepaired: 2, TotalSegments: 48})
+	}))
+	defer server.Close()
+
+	restClient, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	run, err := restClient.UpdateRepairRunState(context.Background(), "123", "RUNNING")
+	if err != nil {
+		t.Fatalf("failed to start repair run: %s", err)
+	}
+
+	if run.State != "RUNNING" || run.SegmentsRepaired != 2 || run.TotalSegments != 48 {
+		t.Errorf("unexpected repair run: %+v", run)
+	}
+}
+
+func TestUpdateRepairRunStateNotModified(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/login":
+			logins++
+		case r.Method == http.MethodPut && r.URL.Path == "/repair_run/123/state/ABORTED":
+			// The run was already aborted, e.g., from the UI.
+			w.WriteHeader(http.StatusNotModified)
+		case r.Method == http.MethodGet && r.URL.Path == "/repair_run/123":
+			_ = json.NewEncoder(w).Encode(RepairRun{Id: "123", State: "ABORTED"})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	restClient, err := NewAuthenticatedClient(server.URL, Credentials{Username: "admin", Password: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	run, err := restClient.UpdateRepairRunState(context.Background(), "123", "ABORTED")
+	if err != nil {
+		t.Fatalf("failed to abort repair run: %s", err)
+	}
+	if run.State != "ABORTED" {
+		t.Errorf("unexpected repair run: %+v", run)
+	}
+	if logins != 1 {
+		t.Errorf("expected 1 login, got %d", logins)
+	}
+}
+
+func TestGetRepairRuns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/repair_run" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("cluster_name") != "test" || r.URL.Query().Get("state") != "RUNNING,DONE" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		_ = json.NewEncoder(w).Encode([]RepairRun{{Id: "123", State: "RUNNING"}, {Id: "456", State: "DONE", EndTime: "2020-11-01T02:00:00Z"}})
+	}))
+	defer server.Close()
+
+	restClient, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	runs, err := restClient.GetRepairRuns(context.Background(), "test", "RUNNING", "DONE")
+	if err != nil {
+		t.Fatalf("failed to get repair runs: %s", err)
+	}
+
+	if len(runs) != 2 || runs[0].Id != "123" || runs[1].EndTime != "2020-11-01T02:00:00Z" {
+		t.Errorf("unexpected repair runs: %+v", runs)
+	}
+}
+
+func TestGetEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/ping":                         "/ping",
+		"/cluster/test":                 "/cluster",
+		"/repair_run/123/state/RUNNING": "/repair_run",
+	}
+	for path, expected := range tests {
+		if endpoint := getEndpoint(path); endpoint != expected {
+			t.Errorf("expected endpoint of %s to be (%s), got (%s)", path, expected, endpoint)
+		}
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	unauthenticated, _ := NewClient("http://reaper:8080")
+	authenticated, _ := NewAuthenticatedClient("http://reaper:8080", Credentials{Username: "admin", Password: "admin"})
+
+	for _, restClient := range []Client{unauthenticated, authenticated} {
+		if timeout := restClient.(*client).httpClient.Timeout; timeout != requestTimeout {
+			t.Errorf("expected timeout (%s), got (%s)", requestTimeout, timeout)
+		}
+	}
+}
+
+func TestDeleteCluster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+		if r.URL.Query().Get("force") != "true" {
+			t.Errorf("expected force=true, got (%s)", r.URL.RawQuery)
+		}
+		if r.URL.Path == "/cluster/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	restClient, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	if err := restClient.DeleteCluster(context.Background(), "test"); err != nil {
+		t.Errorf("failed to delete cluster: %s", err)
+	}
+
+	if err := restClient.DeleteCluster(context.Background(), "missing"); err != reapergo.CassandraClusterNotFound {
+		t.Errorf("expected (%s), got (%s)", reapergo.CassandraClusterNotFound, err)
+	}
+}
+
+func TestGetCluster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cluster/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "test", "seed_hosts": ["test-dc1-service"], "nodes_status": {"endpointStates": [
+			{"sourceNode": "10.0.0.1", "endpoints": {"dc1": {"rack1": [{"endpoint": "10.0.0.1", "dc": "dc1", "rack": "rack1", "status": "NORMAL"}]}}}]}}`))
+	}))
+	defer server.Close()
+
+	restClient, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	cluster, err := restClient.GetCluster(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("failed to get cluster: %s", err)
+	}
+	if cluster.Name != "test" || len(cluster.NodeState.GossipStates) != 1 {
+		t.Fatalf("unexpected cluster: %+v", cluster)
+	}
+	endpoints := cluster.NodeState.GossipStates[0].DataCenters["dc1"].Racks["rack1"].Endpoints
+	if len(endpoints) != 1 || endpoints[0].DataCenter != "dc1" {
+		t.Errorf("unexpected endpoints: %+v", endpoints)
+	}
+
+	if _, err := restClient.GetCluster(context.Background(), "missing"); err != reapergo.CassandraClusterNotFound {
+		t.Errorf("expected (%s), got (%s)", reapergo.CassandraClusterNotFound, err)
+	}
+}
+
+func TestAuthenticatedClient(t *testing.T) {
+	logins := 0
+	session := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			if r.FormValue("username") != "admin" || r.FormValue("password") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			logins++
+			session = fmt.Sprintf("session-%d", logins)
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: session})
+			return
+		}
+
+		if cookie, err := r.Cookie("JSESSIONID"); err != nil || cookie.Value != session {
+			http.Redirect(w, r, "/webui/login.html", http.StatusFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]string{"test"})
+	}))
+	defer server.Close()
+
+	restClient, err := NewAuthenticatedClient(server.URL, Credentials{Username: "admin", Password: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	if _, err := restClient.GetClusterNames(context.Background()); err != nil {
+		t.Fatalf("failed to get cluster names: %s", err)
+	}
+
+	// Expire the session so that the client has to log in again.
+	session = "expired"
+	if names, err := restClient.GetClusterNames(context.Background()); err != nil || len(names) != 1 {
+		t.Fatalf("failed to get cluster names after session expired: %v, %s", names, err)
+	}
+	if logins != 2 {
+		t.Errorf("expected 2 logins, got %d", logins)
+	}
+
+	restClient, err = NewAuthenticatedClient(server.URL, Credentials{Username: "admin", Password: "wrong"})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	if _, err := restClient.GetClusterNames(context.Background()); err == nil {
+		t.Errorf("expected login with invalid credentials to fail")
+	}
+}
@@ -0,0 +1,203 @@
+package reaperclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	reapergo "github.com/jsanda/reaper-client-go/reaper"
+)
+
+// The cluster endpoints mirror reaper-client-go's Client rather than wrapping it. Its http client
+// is unexported and created without a cookie jar, a redirect policy or a timeout, so it can
+// neither log in to Reaper nor keep the session. It also drops the request context and does not
+// check the status code of DeleteCluster, which cannot force the deletion either. Only its
+// ReaperClient interface and types are used so that callers are unaffected.
+
+// The maximum number of clusters that GetClusters fetches concurrently.
+const getClustersConcurrency = 5
+
+// The cluster state as returned by Reaper's REST API.
+type clusterStatus struct {
+	Name string `json:"name"`
+
+	JmxUsername string `json:"jmx_username,omitempty"`
+
+	JmxPasswordSet bool `json:"jmx_password_is_set,omitempty"`
+
+	Seeds []string `json:"seed_hosts,omitempty"`
+
+	NodeStatus struct {
+		EndpointStates []gossipStatus `json:"endpointStates,omitempty"`
+	} `json:"nodes_status"`
+}
+
+type gossipStatus struct {
+	SourceNode string `json:"sourceNode"`
+
+	EndpointNames []string `json:"endpointNames,omitempty"`
+
+	TotalLoad float64 `json:"totalLoad,omitempty"`
+
+	// Endpoints are keyed by data center and then by rack.
+	Endpoints map[string]map[string][]endpointStatus `json:"endpoints"`
+}
+
+type endpointStatus struct {
+	Endpoint string `json:"endpoint"`
+
+	DataCenter string `json:"dc"`
+
+	Rack string `json:"rack"`
+
+	HostId string `json:"hostId"`
+
+	Status string `json:"status"`
+
+	Severity float64 `json:"severity"`
+
+	ReleaseVersion string `json:"releaseVersion"`
+
+	Tokens string `json:"tokens"`
+
+	Load float64 `json:"load"`
+}
+
+func (c *client) IsReaperUp(ctx context.Context) (bool, error) {
+	if err := c.doRequest(ctx, http.MethodGet, "/ping", nil, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *client) GetClusterNames(ctx context.Context) ([]string, error) {
+	clusterNames := []string{}
+	if err := c.doRequest(ctx, http.MethodGet, "/cluster", nil, &clusterNames); err != nil {
+		return nil, fmt.Errorf("failed to get cluster names: %w", err)
+	}
+
+	return clusterNames, nil
+}
+
+// GetCluster returns reapergo.CassandraClusterNotFound if the cluster is not registered.
+func (c *client) GetCluster(ctx context.Context, name string) (*reapergo.Cluster, error) {
+	state := &clusterStatus{}
+	if err := c.doRequest(ctx, http.MethodGet, "/cluster/"+name, nil, state); err != nil {
+		if err == errNotFound {
+			return nil, reapergo.CassandraClusterNotFound
+		}
+		return nil, fmt.Errorf("failed to get cluster (%s): %w", name, err)
+	}
+
+	return newCluster(state), nil
+}
+
+func (c *client) GetClusters(ctx context.Context) <-chan reapergo.GetClusterResult {
+	results := make(chan reapergo.GetClusterResult, getClustersConcurrency)
+
+	clusterNames, err := c.GetClusterNames(ctx)
+	if err != nil {
+		results <- reapergo.GetClusterResult{Error: err}
+		close(results)
+		return results
+	}
+
+	go func() {
+		defer close(results)
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, getClustersConcurrency)
+		for _, clusterName := range clusterNames {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(name string) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				cluster, err := c.GetCluster(ctx, name)
+				results <- reapergo.GetClusterResult{Cluster: cluster, Error: err}
+			}(clusterName)
+		}
+		wg.Wait()
+	}()
+
+	return results
+}
+
+func (c *client) GetClustersSync(ctx context.Context) ([]*reapergo.Cluster, error) {
+	clusters := make([]*reapergo.Cluster, 0)
+
+	for result := range c.GetClusters(ctx) {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		clusters = append(clusters, result.Cluster)
+	}
+
+	return clusters, nil
+}
+
+// AddCluster registers the cluster with Reaper. seed can be a comma-separated list of hosts.
+// If the cluster is already registered, Reaper replaces its seeds.
+func (c *client) AddCluster(ctx context.Context, cluster string, seed string) error {
+	params := url.Values{}
+	params.Set("seedHost", seed)
+
+	if err := c.doRequest(ctx, http.MethodPut, "/cluster/"+cluster, params, nil); err != nil {
+		return fmt.Errorf("failed to add cluster (%s): %w", cluster, err)
+	}
+
+	return nil
+}
+
+// DeleteCluster removes the cluster from Reaper along with its repair schedules and repair runs.
+// Returns reapergo.CassandraClusterNotFound if the cluster is not registered.
+func (c *client) DeleteCluster(ctx context.Context, cluster string) error {
+	// Without force Reaper refuses to delete a cluster that still has repair schedules or runs.
+	params := url.Values{}
+	params.Set("force", "true")
+
+	if err := c.doRequest(ctx, http.MethodDelete, "/cluster/"+cluster, params, nil); err != nil {
+		if err == errNotFound {
+			return reapergo.CassandraClusterNotFound
+		}
+		return fmt.Errorf("failed to delete cluster (%s): %w", cluster, err)
+	}
+
+	return nil
+}
+
+func newCluster(state *clusterStatus) *reapergo.Cluster {
+	cluster := &reapergo.Cluster{
+		Name:           state.Name,
+		JmxUsername:    state.JmxUsername,
+		JmxPasswordSet: state.JmxPasswordSet,
+		Seeds:          state.Seeds,
+	}
+
+	for _, gs := range state.NodeStatus.EndpointStates {
+		gossipState := reapergo.GossipState{
+			SourceNode:    gs.SourceNode,
+			EndpointNames: gs.EndpointNames,
+			TotalLoad:     gs.TotalLoad,
+			DataCenters:   map[string]reapergo.DataCenterState{},
+		}
+		for dc, racks := range gs.Endpoints {
+			dcState := reapergo.DataCenterState{Name: dc, Racks: map[string]reapergo.RackState{}}
+			for rack, endpoints := range racks {
+				rackState := reapergo.RackState{Name: rack}
+				for _, ep := range endpoints {
+					rackState.Endpoints = append(rackState.Endpoints, reapergo.EndpointState(ep))
+				}
+				dcState.Racks[rack] = rackState
+			}
+			gossipState.DataCenters[dc] = dcState
+		}
+		cluster.NodeState.GossipStates = append(cluster.NodeState.GossipStates, gossipState)
+	}
+
+	return cluster
+}
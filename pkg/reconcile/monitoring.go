@@ -0,0 +1,196 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"github.com/thelastpickle/reaper-operator/pkg/events"
+	"github.com/thelastpickle/reaper-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Reaper serves its Prometheus metrics on the admin port.
+const metricsPath = "/prometheusMetrics"
+
+// The Prometheus Operator's types are used as unstructured objects since its CRDs are optional.
+var (
+	serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+)
+
+// Creates, updates or deletes the ServiceMonitor and the PrometheusRule of the Reaper according
+// to spec.monitoring. Nothing is done if the monitoring.coreos.com CRDs are not installed.
+func (r *defaultReconciler) ReconcileMonitoring(ctx context.Context, req ReaperRequest) (*ctrl.Result, error) {
+	reaper := req.Reaper
+
+	var serviceMonitor, prometheusRule *unstructured.Unstructured
+	if monitoring := reaper.Spec.Monitoring; monitoring != nil {
+		serviceMonitor = newServiceMonitor(reaper)
+		if !monitoring.DisableAlerts {
+			prometheusRule = newPrometheusRule(reaper)
+		}
+	}
+
+	if result, err := r.reconcileMonitoringObject(ctx, req, serviceMonitorGVK, serviceMonitor); result != nil {
+		return result, err
+	}
+	return r.reconcileMonitoringObject(ctx, req, prometheusRuleGVK, prometheusRule)
+}
+
+// Creates or updates the object if desired is not nil, and deletes the object of the Reaper
+// otherwise.
+func (r *defaultReconciler) reconcileMonitoringObject(ctx context.Context, req ReaperRequest, gvk schema.GroupVersionKind, desired *unstructured.Unstructured) (*ctrl.Result, error) {
+	reaper := req.Reaper
+	key := types.NamespacedName{Namespace: reaper.Namespace, Name: reaper.Name}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(gvk)
+	err := r.Get(ctx, key, current)
+	if meta.IsNoMatchError(err) {
+		if desired != nil {
+			req.Logger.Info("monitoring.coreos.com CRDs are not installed, skipping monitoring", "kind", gvk.Kind)
+		}
+		return nil, nil
+	} else if errors.IsNotFound(err) {
+		if desired == nil {
+			return nil, nil
+		}
+
+		if err = controllerutil.SetControllerReference(reaper, desired, r.scheme); err != nil {
+			req.Logger.Error(err, "failed to set owner reference", "kind", gvk.Kind, "name", key)
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+
+		req.Logger.Info("creating monitoring object", "kind", gvk.Kind, "name", key)
+		if err = r.Create(ctx, desired); err != nil {
+			req.Logger.Error(err, "failed to create monitoring object", "kind", gvk.Kind, "name", key)
+			r.recorder.Eventf(reaper, corev1.EventTypeWarning, events.CreateFailedReason, "failed to create %s %s: %s", gvk.Kind, key.Name, err)
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.CreatedReason, "created %s %s", gvk.Kind, key.Name)
+		return nil, nil
+	} else if err != nil {
+		req.Logger.Error(err, "failed to get monitoring object", "kind", gvk.Kind, "name", key)
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+
+	if desired == nil {
+		// Only the objects that the operator created are deleted.
+		if !metav1.IsControlledBy(current, reaper) {
+			return nil, nil
+		}
+
+		req.Logger.Info("deleting monitoring object", "kind", gvk.Kind, "name", key)
+		if err = r.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+			req.Logger.Error(err, "failed to delete monitoring object", "kind", gvk.Kind, "name", key)
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.DeletedReason, "deleted %s %s", gvk.Kind, key.Name)
+		return nil, nil
+	}
+
+	if util.ResourcesHaveSameHash(desired, current) {
+		return nil, nil
+	}
+
+	req.Logger.Info("updating monitoring object", "kind", gvk.Kind, "name", key)
+	current.SetLabels(util.MergeMap(map[string]string{}, current.GetLabels(), desired.GetLabels()))
+	current.SetAnnotations(util.MergeMap(map[string]string{}, current.GetAnnotations(), desired.GetAnnotations()))
+	current.Object["spec"] = desired.Object["spec"]
+
+	if err = r.Update(ctx, current); err != nil {
+		req.Logger.Error(err, "failed to update monitoring object", "kind", gvk.Kind, "name", key)
+		r.recorder.Eventf(reaper, corev1.EventTypeWarning, events.UpdateFailedReason, "failed to update %s %s: %s", gvk.Kind, key.Name, err)
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+	r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.UpdatedReason, "updated %s %s", gvk.Kind, key.Name)
+	return nil, nil
+}
+
+func newMonitoringObject(reaper *api.Reaper, gvk schema.GroupVersionKind, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(reaper.Namespace)
+	obj.SetName(reaper.Name)
+	obj.SetLabels(util.MergeMap(map[string]string{}, reaper.Spec.Monitoring.Labels, createLabels(reaper)))
+	util.AddHashAnnotation(obj)
+
+	return obj
+}
+
+// Returns a ServiceMonitor that scrapes the admin port of the Reaper service.
+func newServiceMonitor(reaper *api.Reaper) *unstructured.Unstructured {
+	endpoint := map[string]interface{}{
+		"port": "admin",
+		"path": metricsPath,
+	}
+	if interval := reaper.Spec.Monitoring.Interval; len(interval) > 0 {
+		endpoint["interval"] = interval
+	}
+
+	selectorLabels := map[string]interface{}{}
+	for k, v := range createLabels(reaper) {
+		selectorLabels[k] = v
+	}
+
+	return newMonitoringObject(reaper, serviceMonitorGVK, map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": selectorLabels,
+		},
+		"endpoints": []interface{}{endpoint},
+	})
+}
+
+// Returns a PrometheusRule with alerts for the Reaper. The alerts select the series that the
+// ServiceMonitor scrapes by the namespace and the service of the Reaper.
+func newPrometheusRule(reaper *api.Reaper) *unstructured.Unstructured {
+	selector := fmt.Sprintf(`namespace="%s", service="%s"`, reaper.Namespace, GetServiceName(reaper.Name))
+
+	newAlert := func(name, expr, duration, severity, summary string) map[string]interface{} {
+		return map[string]interface{}{
+			"alert": name,
+			"expr":  expr,
+			"for":   duration,
+			"labels": map[string]interface{}{
+				"severity": severity,
+			},
+			"annotations": map[string]interface{}{
+				"summary": summary,
+			},
+		}
+	}
+
+	rules := []interface{}{
+		newAlert("ReaperDown",
+			fmt.Sprintf(`absent(up{%s} == 1)`, selector),
+			"5m", "critical",
+			fmt.Sprintf("Reaper %s/%s is down", reaper.Namespace, reaper.Name)),
+		newAlert("ReaperRepairStuck",
+			fmt.Sprintf(`changes(io_cassandrareaper_service_RepairRunner_repairProgress{%[1]s}[1h]) == 0 and io_cassandrareaper_service_RepairRunner_repairProgress{%[1]s} < 1`, selector),
+			"15m", "warning",
+			"Repair run {{ $labels.runid }} of cluster {{ $labels.cluster }} has made no progress for over an hour"),
+		newAlert("ReaperSegmentFailures",
+			fmt.Sprintf(`increase(io_cassandrareaper_service_SegmentRunner_postpone{%s}[1h]) > 10`, selector),
+			"15m", "warning",
+			"Repair segments of cluster {{ $labels.cluster }} keep failing and are being postponed"),
+	}
+
+	return newMonitoringObject(reaper, prometheusRuleGVK, map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name":  fmt.Sprintf("reaper-%s-%s", reaper.Namespace, reaper.Name),
+				"rules": rules,
+			},
+		},
+	})
+}
@@ -0,0 +1,66 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNewServiceWithMonitoring(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.Monitoring = &api.ReaperMonitoring{}
+	key := types.NamespacedName{Namespace: reaper.Namespace, Name: GetServiceName(reaper.Name)}
+
+	service := newService(key, reaper)
+
+	assert.Equal(t, 2, len(service.Spec.Ports))
+	assert.Equal(t, "admin", service.Spec.Ports[1].Name)
+}
+
+func TestNewServiceMonitor(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.Monitoring = &api.ReaperMonitoring{
+		Labels:   map[string]string{"release": "prometheus"},
+		Interval: "30s",
+	}
+
+	serviceMonitor := newServiceMonitor(reaper)
+	// Panics if the object contains types that cannot be deep copied.
+	serviceMonitor = serviceMonitor.DeepCopy()
+
+	assert.Equal(t, serviceMonitorGVK, serviceMonitor.GroupVersionKind())
+	assert.Equal(t, reaper.Namespace, serviceMonitor.GetNamespace())
+	assert.Equal(t, reaper.Name, serviceMonitor.GetName())
+	assert.Equal(t, "prometheus", serviceMonitor.GetLabels()["release"])
+
+	endpoints, _, _ := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"port": "admin", "path": metricsPath, "interval": "30s"},
+	}, endpoints)
+
+	selector, _, _ := unstructured.NestedStringMap(serviceMonitor.Object, "spec", "selector", "matchLabels")
+	assert.Equal(t, createLabels(reaper), selector)
+}
+
+func TestNewPrometheusRule(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.Monitoring = &api.ReaperMonitoring{}
+
+	prometheusRule := newPrometheusRule(reaper).DeepCopy()
+
+	assert.Equal(t, prometheusRuleGVK, prometheusRule.GroupVersionKind())
+
+	groups, _, _ := unstructured.NestedSlice(prometheusRule.Object, "spec", "groups")
+	assert.Equal(t, 1, len(groups))
+
+	rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+	alerts := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		alerts = append(alerts, rule.(map[string]interface{})["alert"].(string))
+	}
+	assert.Equal(t, []string{"ReaperDown", "ReaperRepairStuck", "ReaperSegmentFailures"}, alerts)
+	assert.Equal(t, `absent(up{namespace="service-test", service="test-reaper-reaper-service"} == 1)`, rules[0].(map[string]interface{})["expr"])
+}
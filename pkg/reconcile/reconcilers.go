@@ -3,12 +3,20 @@ package reconcile
 import (
 	"context"
 	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	"github.com/go-logr/logr"
 	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
 	"github.com/thelastpickle/reaper-operator/pkg/config"
+	"github.com/thelastpickle/reaper-operator/pkg/events"
 	mlabels "github.com/thelastpickle/reaper-operator/pkg/labels"
+	"github.com/thelastpickle/reaper-operator/pkg/metrics"
 	"github.com/thelastpickle/reaper-operator/pkg/status"
 	"github.com/thelastpickle/reaper-operator/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
@@ -19,14 +27,28 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
-	schemaJobImage           = "jsanda/create_keyspace:latest"
-	schemaJobImagePullPolicy = corev1.PullIfNotPresent
+	// The delay before a failed schema job is first recreated. It doubles with every failure
+	// up to maxSchemaJobRetryDelay.
+	schemaJobRetryDelay    = 10 * time.Second
+	maxSchemaJobRetryDelay = 5 * time.Minute
+
+	// The hash of the keyspace and replication that the schema jobs were created with.
+	schemaHashAnnotation = "cassandra-reaper.io/schema-hash"
+
+	// A hash of the resource versions of the secrets that the Reaper references. It is set on
+	// the pod template so that the pods are restarted when a secret changes, since Reaper reads
+	// the secrets through env vars.
+	secretsHashAnnotation = "reaper.cassandra-reaper.io/secrets-hash"
+
+	tlsVolumeName = "reaper-tls"
+	tlsMountPath  = "/etc/reaper/tls"
 )
 
 // ReaperRequest containers the information necessary to perform reconciliation actions on a Reaper object.
@@ -44,26 +66,40 @@ type ServiceReconciler interface {
 
 type SchemaReconciler interface {
 	ReconcileSchema(ctx context.Context, req ReaperRequest) (*ctrl.Result, error)
+
+	DeleteSchema(ctx context.Context, req ReaperRequest) (*ctrl.Result, error)
 }
 
 type DeploymentReconciler interface {
 	ReconcileDeployment(ctx context.Context, req ReaperRequest) (*ctrl.Result, error)
 }
 
+type MonitoringReconciler interface {
+	ReconcileMonitoring(ctx context.Context, req ReaperRequest) (*ctrl.Result, error)
+}
+
+type SidecarReconciler interface {
+	ReconcileSidecar(ctx context.Context, req ReaperRequest, cassdc *cassdcv1beta1.CassandraDatacenter) (bool, error)
+}
+
 type defaultReconciler struct {
 	client.Client
 
 	scheme *runtime.Scheme
 
+	// Records the events of the reconcilers on the Reaper.
+	recorder record.EventRecorder
+
 	secretsManager SecretsManager
 }
 
 var reconciler defaultReconciler
 
-func InitReconcilers(client client.Client, scheme *runtime.Scheme) {
+func InitReconcilers(client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder) {
 	reconciler = defaultReconciler{
 		Client:         client,
 		scheme:         scheme,
+		recorder:       recorder,
 		secretsManager: NewSecretsManager(),
 	}
 }
@@ -80,17 +116,28 @@ func GetDeploymentReconciler() DeploymentReconciler {
 	return &reconciler
 }
 
+func GetMonitoringReconciler() MonitoringReconciler {
+	return &reconciler
+}
+
+func GetSidecarReconciler() SidecarReconciler {
+	return &reconciler
+}
+
 func (r *defaultReconciler) ReconcileService(ctx context.Context, req ReaperRequest) (*ctrl.Result, error) {
 	reaper := req.Reaper
 	key := types.NamespacedName{Namespace: reaper.Namespace, Name: GetServiceName(reaper.Name)}
 
 	req.Logger.Info("reconciling service", "service", key)
 
+	desiredService := newService(key, reaper)
+	util.AddHashAnnotation(desiredService)
+
 	service := &corev1.Service{}
 	err := r.Client.Get(ctx, key, service)
 	if err != nil && errors.IsNotFound(err) {
 		// create the service
-		service = newService(key, reaper)
+		service = desiredService
 		if err = controllerutil.SetControllerReference(reaper, service, r.scheme); err != nil {
 			req.Logger.Error(err, "failed to set owner reference on service", "service", key)
 			return &ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
@@ -99,18 +146,77 @@ func (r *defaultReconciler) ReconcileService(ctx context.Context, req ReaperRequ
 		req.Logger.Info("creating service", "service", key)
 		if err = r.Client.Create(ctx, service); err != nil {
 			req.Logger.Error(err, "failed to create service", "service", key)
+			r.recorder.Eventf(reaper, corev1.EventTypeWarning, events.CreateFailedReason, "failed to create service %s: %s", key.Name, err)
+			r.setCondition(ctx, req, api.ServiceReady, corev1.ConditionFalse, status.ServiceCreateFailedReason, err.Error())
 			return &ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
 		}
-
-		return nil, nil
+		r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.CreatedReason, "created service %s", key.Name)
 	} else if err != nil {
 		req.Logger.Error(err, "failed to get service", "service", key)
 		return &ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
+	} else if !util.ResourcesHaveSameHash(desiredService, service) || serviceSpecChanged(service.Spec, desiredService.Spec) {
+		req.Logger.Info("updating service", "service", key)
+
+		service.Labels = util.MergeMap(map[string]string{}, service.Labels, desiredService.Labels)
+		service.Annotations = util.MergeMap(map[string]string{}, service.Annotations, desiredService.Annotations)
+		updateServiceSpec(&service.Spec, desiredService.Spec)
+
+		if err = r.Client.Update(ctx, service); err != nil {
+			req.Logger.Error(err, "failed to update service", "service", key)
+			r.recorder.Eventf(reaper, corev1.EventTypeWarning, events.UpdateFailedReason, "failed to update service %s: %s", key.Name, err)
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.UpdatedReason, "updated service %s", key.Name)
+	}
+
+	if err = req.StatusManager.SetCondition(ctx, reaper, api.ServiceReady, corev1.ConditionTrue, status.ServiceCreatedReason, ""); err != nil {
+		req.Logger.Error(err, "failed to update status")
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
 	}
 
 	return nil, nil
 }
 
+// Copies the fields that the operator manages from desired to current. The cluster IP is
+// immutable and is left as is. Node ports that were allocated for ports that are still
+// exposed are kept so that they do not change on every update.
+func updateServiceSpec(current *corev1.ServiceSpec, desired corev1.ServiceSpec) {
+	ports := make([]corev1.ServicePort, 0, len(desired.Ports))
+	for _, port := range desired.Ports {
+		if desired.Type != corev1.ServiceTypeClusterIP {
+			for _, currentPort := range current.Ports {
+				if currentPort.Name == port.Name {
+					port.NodePort = currentPort.NodePort
+				}
+			}
+		}
+		ports = append(ports, port)
+	}
+
+	current.Type = desired.Type
+	current.Ports = ports
+	current.Selector = desired.Selector
+}
+
+// Returns true if the fields that the operator manages differ between current and desired, e.g.
+// because the service was edited without changing its hash annotation. Allocated node ports are
+// ignored.
+func serviceSpecChanged(current, desired corev1.ServiceSpec) bool {
+	updated := *current.DeepCopy()
+	updateServiceSpec(&updated, desired)
+
+	return current.Type != updated.Type || !reflect.DeepEqual(current.Ports, updated.Ports) ||
+		!reflect.DeepEqual(current.Selector, updated.Selector)
+}
+
+// Sets the condition while already handling a failure. An error updating the status is only
+// logged so that the original error is the one that is returned.
+func (r *defaultReconciler) setCondition(ctx context.Context, req ReaperRequest, condType api.ReaperConditionType, condStatus corev1.ConditionStatus, reason, message string) {
+	if err := req.StatusManager.SetCondition(ctx, req.Reaper, condType, condStatus, reason, message); err != nil {
+		req.Logger.Error(err, "failed to update status", "condition", condType)
+	}
+}
+
 func GetServiceName(reaperName string) string {
 	return reaperName + "-reaper-service"
 }
@@ -118,29 +224,57 @@ func GetServiceName(reaperName string) string {
 func newService(key types.NamespacedName, reaper *api.Reaper) *corev1.Service {
 	labels := createLabels(reaper)
 
+	serviceType := reaper.Spec.Service.Type
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeClusterIP
+	}
+
+	ports := []corev1.ServicePort{
+		{
+			Port:     8080,
+			Name:     "app",
+			Protocol: corev1.ProtocolTCP,
+			TargetPort: intstr.IntOrString{
+				Type:   intstr.String,
+				StrVal: "app",
+			},
+		},
+	}
+	// The ServiceMonitor scrapes the admin port.
+	if reaper.Spec.Service.ExposeAdminPort || reaper.Spec.Monitoring != nil {
+		ports = append(ports, corev1.ServicePort{
+			Port:     8081,
+			Name:     "admin",
+			Protocol: corev1.ProtocolTCP,
+			TargetPort: intstr.IntOrString{
+				Type:   intstr.String,
+				StrVal: "admin",
+			},
+		})
+	}
+
+	// The Cassandra pods get the Reaper label when the sidecar is injected, but cass-operator
+	// sets their managed-by label.
+	selector := labels
+	if reaper.Spec.ServerConfig.DatacenterAvailability == api.DatacenterAvailabilitySidecar {
+		selector = map[string]string{mlabels.ReaperLabel: reaper.Name}
+	}
+
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Service",
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      key.Name,
-			Namespace: key.Namespace,
-			Labels:    labels,
+			Name:        key.Name,
+			Namespace:   key.Namespace,
+			Labels:      labels,
+			Annotations: util.MergeMap(map[string]string{}, reaper.Spec.Service.Annotations),
 		},
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
-				{
-					Port:     8080,
-					Name:     "app",
-					Protocol: corev1.ProtocolTCP,
-					TargetPort: intstr.IntOrString{
-						Type:   intstr.String,
-						StrVal: "app",
-					},
-				},
-			},
-			Selector: labels,
+			Type:     serviceType,
+			Ports:    ports,
+			Selector: selector,
 		},
 	}
 }
@@ -153,36 +287,275 @@ func (r *defaultReconciler) ReconcileSchema(ctx context.Context, req ReaperReque
 
 	if reaper.Spec.ServerConfig.StorageType == api.StorageTypeMemory {
 		// No need to run schema job when using in-memory backend
+		if err := req.StatusManager.SetCondition(ctx, reaper, api.SchemaInitialized, corev1.ConditionTrue, status.SchemaNotRequiredReason, "the memory backend does not require a schema"); err != nil {
+			req.Logger.Error(err, "failed to update status")
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
 		return nil, nil
 	}
 
+	if reaper.Status.Replication != nil {
+		// The keyspace has been created.
+		return r.reconcileReplication(ctx, req)
+	}
+
 	schemaJob := &v1batch.Job{}
 	err := r.Client.Get(ctx, key, schemaJob)
 	if err != nil && errors.IsNotFound(err) {
-		return r.createSchemaJob(ctx, schemaJob, req)
+		return r.createSchemaJob(ctx, newSchemaJob(reaper), req)
 	} else if !jobFinished(schemaJob) {
 		req.Logger.Info("schema job not finished", "job", key)
-		return &ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
+		r.setCondition(ctx, req, api.SchemaInitialized, corev1.ConditionFalse, status.SchemaJobRunningReason, fmt.Sprintf("waiting for job %s to finish", key.Name))
+		// The job is owned by the Reaper, so the Reaper is reconciled again when it finishes.
+		return &ctrl.Result{}, nil
 	} else if jobFailed(schemaJob) {
-		req.Logger.Info("schema job failed. deleting it so can be recreated to try again.", "job", key)
-		if err = r.Delete(ctx, schemaJob); err == nil {
-			return &ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-		} else {
-			req.Logger.Error(err, "failed to delete schema job", "job", key)
-			return &ctrl.Result{RequeueAfter: 5 * time.Second}, err
-		}
+		return r.retrySchemaJob(ctx, schemaJob, req)
+	} else if !jobHasSchemaHash(schemaJob, reaper) {
+		// The replication changed while the keyspace was being created, or the job was
+		// created by an earlier version of the operator.
+		return r.reconcileReplication(ctx, req)
 	} else {
 		// the job completed successfully
 		req.Logger.Info("schema job completed successfully", "job", key)
+		replication := reaper.Spec.ServerConfig.CassandraBackend.Replication
+		if err = req.StatusManager.SetSchemaApplied(ctx, reaper, replication, status.SchemaJobCompletedReason, ""); err != nil {
+			req.Logger.Error(err, "failed to update status")
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.SchemaAppliedReason, "created keyspace %s", reaper.Spec.ServerConfig.CassandraBackend.Keyspace)
 		return nil, nil
 	}
 }
 
+// Runs a job that alters the replication of the keyspace when it differs from the replication
+// last applied. The job is recreated if the replication changes again while it exists.
+func (r *defaultReconciler) reconcileReplication(ctx context.Context, req ReaperRequest) (*ctrl.Result, error) {
+	reaper := req.Reaper
+	replication := reaper.Spec.ServerConfig.CassandraBackend.Replication
+	if applied := reaper.Status.Replication; applied != nil && reflect.DeepEqual(*applied, replication) {
+		return nil, nil
+	}
+
+	key := types.NamespacedName{Namespace: reaper.Namespace, Name: getAlterKeyspaceJobName(reaper)}
+	job := &v1batch.Job{}
+	if err := r.Client.Get(ctx, key, job); err != nil {
+		if errors.IsNotFound(err) {
+			req.Logger.Info("replication changed, altering keyspace", "job", key, "replication", config.ReplicationToString(replication))
+			return r.createSchemaJob(ctx, newAlterKeyspaceJob(reaper), req)
+		}
+		req.Logger.Error(err, "failed to get alter keyspace job", "job", key)
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+
+	if !jobHasSchemaHash(job, reaper) {
+		req.Logger.Info("replication changed again, deleting alter keyspace job", "job", key)
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			req.Logger.Error(err, "failed to delete alter keyspace job", "job", key)
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, err
+		}
+		return &ctrl.Result{}, nil
+	}
+
+	if !jobFinished(job) {
+		req.Logger.Info("alter keyspace job not finished", "job", key)
+		r.setCondition(ctx, req, api.SchemaInitialized, corev1.ConditionTrue, status.ReplicationUpdatingReason, fmt.Sprintf("waiting for job %s to finish", key.Name))
+		return &ctrl.Result{}, nil
+	}
+
+	if jobFailed(job) {
+		return r.retrySchemaJob(ctx, job, req)
+	}
+
+	req.Logger.Info("alter keyspace job completed successfully", "job", key)
+	message := fmt.Sprintf("replication of keyspace %s updated", reaper.Spec.ServerConfig.CassandraBackend.Keyspace)
+	if err := req.StatusManager.SetSchemaApplied(ctx, reaper, replication, status.ReplicationUpdatedReason, message); err != nil {
+		req.Logger.Error(err, "failed to update status")
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+	r.recorder.Event(reaper, corev1.EventTypeNormal, events.SchemaAppliedReason, message)
+	return nil, nil
+}
+
+// Drops the keyspace from the Cassandra backend when a Reaper with the Delete deletion policy is
+// deleted. A nil result is returned once the keyspace is dropped or if there is nothing to drop.
+// If the job fails, it is deleted so that it is recreated on the next attempt and an error with
+// the failure is returned.
+func (r *defaultReconciler) DeleteSchema(ctx context.Context, req ReaperRequest) (*ctrl.Result, error) {
+	reaper := req.Reaper
+	cfg := reaper.Spec.ServerConfig
+	if reaper.Spec.DeletionPolicy != api.DeletionPolicyDelete || cfg.StorageType != api.StorageTypeCassandra || cfg.CassandraBackend == nil {
+		return nil, nil
+	}
+
+	key := types.NamespacedName{Namespace: reaper.Namespace, Name: getDropKeyspaceJobName(reaper)}
+	job := &v1batch.Job{}
+	if err := r.Client.Get(ctx, key, job); err != nil {
+		if errors.IsNotFound(err) {
+			req.Logger.Info("dropping keyspace", "job", key, "keyspace", cfg.CassandraBackend.Keyspace)
+			return r.createSchemaJob(ctx, newDropKeyspaceJob(reaper), req)
+		}
+		req.Logger.Error(err, "failed to get drop keyspace job", "job", key)
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+
+	if !jobFinished(job) {
+		req.Logger.Info("drop keyspace job not finished", "job", key)
+		return &ctrl.Result{}, nil
+	}
+
+	if jobFailed(job) {
+		reason := r.getSchemaJobFailureMessage(ctx, job)
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			req.Logger.Error(err, "failed to delete drop keyspace job", "job", key)
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, err
+		}
+		return &ctrl.Result{}, fmt.Errorf("job %s failed to drop keyspace %s: %s", key.Name, cfg.CassandraBackend.Keyspace, reason)
+	}
+
+	req.Logger.Info("drop keyspace job completed successfully", "job", key)
+	r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.KeyspaceDroppedReason, "dropped keyspace %s", cfg.CassandraBackend.Keyspace)
+	return nil, nil
+}
+
+// Deletes the failed schema job so that it gets recreated. The delay before the job is deleted
+// doubles with every failure, and the job is left in place once the maximum number of attempts
+// is reached. The failures are counted again after the Reaper spec changes.
+func (r *defaultReconciler) retrySchemaJob(ctx context.Context, schemaJob *v1batch.Job, req ReaperRequest) (*ctrl.Result, error) {
+	reaper := req.Reaper
+	key := types.NamespacedName{Namespace: schemaJob.Namespace, Name: schemaJob.Name}
+	reason := r.getSchemaJobFailureMessage(ctx, schemaJob)
+
+	failures := reaper.Status.SchemaJobFailures
+	if cond := status.GetCondition(&reaper.Status, api.SchemaInitialized); cond != nil && cond.ObservedGeneration != reaper.Generation {
+		failures = 0
+	}
+
+	maxAttempts := int32(api.DefaultSchemaJobMaxAttempts)
+	if reaper.Spec.SchemaJob.MaxAttempts != nil {
+		maxAttempts = *reaper.Spec.SchemaJob.MaxAttempts
+	}
+
+	if failures >= maxAttempts {
+		req.Logger.Info("schema job failed and will not be retried", "job", key, "failures", failures)
+		message := fmt.Sprintf("job %s failed after %d attempts: %s", key.Name, failures+1, reason)
+		if err := req.StatusManager.SetSchemaJobStatus(ctx, reaper, failures, corev1.ConditionFalse, status.SchemaJobFailedReason, message); err != nil {
+			req.Logger.Error(err, "failed to update status")
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		r.recorder.Event(reaper, corev1.EventTypeWarning, events.SchemaJobFailedReason, message)
+		// The reaper is reconciled again when its spec changes.
+		return &ctrl.Result{}, nil
+	}
+
+	retryTime := getJobFailureTime(schemaJob).Add(getSchemaJobRetryDelay(failures))
+	if remaining := time.Until(retryTime); remaining > 0 {
+		// The retry time rather than the remaining delay is reported so that the condition
+		// does not change on every requeue.
+		message := fmt.Sprintf("job %s failed and will be retried at %s: %s", key.Name, retryTime.UTC().Format(time.RFC3339), reason)
+		r.setCondition(ctx, req, api.SchemaInitialized, corev1.ConditionFalse, status.SchemaJobFailedReason, message)
+		return &ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	req.Logger.Info("schema job failed. deleting it so can be recreated to try again.", "job", key)
+	message := fmt.Sprintf("job %s failed and is being retried: %s", key.Name, reason)
+	if err := req.StatusManager.SetSchemaJobStatus(ctx, reaper, failures+1, corev1.ConditionFalse, status.SchemaJobFailedReason, message); err != nil {
+		req.Logger.Error(err, "failed to update status")
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+	r.recorder.Event(reaper, corev1.EventTypeWarning, events.SchemaJobFailedReason, message)
+
+	// Delete the pods along with the job so that they do not linger.
+	if err := r.Delete(ctx, schemaJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		req.Logger.Error(err, "failed to delete schema job", "job", key)
+		return &ctrl.Result{RequeueAfter: 5 * time.Second}, err
+	}
+	return &ctrl.Result{}, nil
+}
+
+// Returns the termination message of the most recently failed pod of the job. The message of
+// the job's failed condition is returned if there is no such pod.
+func (r *defaultReconciler) getSchemaJobFailureMessage(ctx context.Context, job *v1batch.Job) string {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err == nil {
+		var latest *corev1.ContainerStateTerminated
+		for _, pod := range pods.Items {
+			for _, containerStatus := range pod.Status.ContainerStatuses {
+				terminated := containerStatus.State.Terminated
+				if terminated == nil {
+					terminated = containerStatus.LastTerminationState.Terminated
+				}
+				if terminated != nil && terminated.ExitCode != 0 && (latest == nil || latest.FinishedAt.Before(&terminated.FinishedAt)) {
+					latest = terminated
+				}
+			}
+		}
+		if latest != nil {
+			if len(latest.Message) > 0 {
+				return strings.TrimSpace(latest.Message)
+			}
+			return fmt.Sprintf("container exited with code %d (%s)", latest.ExitCode, latest.Reason)
+		}
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == v1batch.JobFailed {
+			return cond.Message
+		}
+	}
+	return "unknown failure"
+}
+
+func getSchemaJobRetryDelay(failures int32) time.Duration {
+	delay := schemaJobRetryDelay
+	for i := int32(0); i < failures && delay < maxSchemaJobRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxSchemaJobRetryDelay {
+		return maxSchemaJobRetryDelay
+	}
+	return delay
+}
+
+func getJobFailureTime(job *v1batch.Job) time.Time {
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == v1batch.JobFailed && cond.Status == corev1.ConditionTrue {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
+
+// Adds the Cassandra credentials and TLS settings to the job and creates it.
 func (r *defaultReconciler) createSchemaJob(ctx context.Context, schemaJob *v1batch.Job, req ReaperRequest) (*ctrl.Result, error) {
 	reaper := req.Reaper
-	schemaJob = newSchemaJob(reaper)
 	key := types.NamespacedName{Namespace: schemaJob.Namespace, Name: schemaJob.Name}
 
+	usernameEnvVar, passwordEnvVar, err := r.getCassandraAuthCredentials(reaper)
+	if err != nil {
+		req.Logger.Error(err, "failed to get cassandra credentials", "job", key)
+		r.recorder.Event(reaper, corev1.EventTypeWarning, events.SecretErrorReason, err.Error())
+		r.setCondition(ctx, req, api.SchemaInitialized, corev1.ConditionFalse, status.SchemaJobCreateFailedReason, err.Error())
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+	if usernameEnvVar != nil {
+		// The job reads the credentials from different env vars than Reaper.
+		usernameEnvVar.Name = "USERNAME"
+		passwordEnvVar.Name = "PASSWORD"
+		container := &schemaJob.Spec.Template.Spec.Containers[0]
+		container.Env = append(container.Env, *usernameEnvVar, *passwordEnvVar)
+	}
+
+	if tls := reaper.Spec.ServerConfig.TLS; tls != nil && tls.CQLEnabled {
+		secret, err := r.getTLSSecret(reaper, true)
+		if err != nil {
+			req.Logger.Error(err, "failed to get tls secret", "job", key)
+			r.recorder.Event(reaper, corev1.EventTypeWarning, events.SecretErrorReason, err.Error())
+			r.setCondition(ctx, req, api.SchemaInitialized, corev1.ConditionFalse, status.SchemaJobCreateFailedReason, err.Error())
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		addSchemaJobTLS(schemaJob, secret)
+	}
+
 	req.Logger.Info("creating schema job", "job", key)
 
 	if err := controllerutil.SetControllerReference(reaper, schemaJob, r.scheme); err != nil {
@@ -192,9 +565,13 @@ func (r *defaultReconciler) createSchemaJob(ctx context.Context, schemaJob *v1ba
 	req.Logger.Info("creating schema job", "job", key)
 	if err := r.Client.Create(ctx, schemaJob); err != nil {
 		req.Logger.Error(err, "failed to create schema job", "job", key)
+		r.recorder.Eventf(reaper, corev1.EventTypeWarning, events.CreateFailedReason, "failed to create job %s: %s", key.Name, err)
+		r.setCondition(ctx, req, api.SchemaInitialized, corev1.ConditionFalse, status.SchemaJobCreateFailedReason, err.Error())
 		return &ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
 	} else {
-		return &ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
+		r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.CreatedReason, "created job %s", key.Name)
+		metrics.IncSchemaJobAttempts(reaper.Namespace, reaper.Name, key.Name)
+		return &ctrl.Result{}, nil
 	}
 }
 
@@ -202,41 +579,135 @@ func getSchemaJobName(r *api.Reaper) string {
 	return fmt.Sprintf("%s-schema", r.Name)
 }
 
+func getAlterKeyspaceJobName(r *api.Reaper) string {
+	return fmt.Sprintf("%s-alter-keyspace", r.Name)
+}
+
+func getDropKeyspaceJobName(r *api.Reaper) string {
+	return fmt.Sprintf("%s-drop-keyspace", r.Name)
+}
+
+// Returns a hash of the inputs of the schema jobs which is used to detect replication changes.
+func getSchemaHash(reaper *api.Reaper) string {
+	cassandra := reaper.Spec.ServerConfig.CassandraBackend
+	return util.DeepHashString(struct {
+		Keyspace    string
+		Replication api.ReplicationConfig
+	}{cassandra.Keyspace, cassandra.Replication})
+}
+
+func jobHasSchemaHash(job *v1batch.Job, reaper *api.Reaper) bool {
+	return job.Annotations[schemaHashAnnotation] == getSchemaHash(reaper)
+}
+
+// Creates a job that runs cqlsh to set the replication of the existing keyspace. The job reads
+// the same env vars as the schema job.
+func newAlterKeyspaceJob(reaper *api.Reaper) *v1batch.Job {
+	job := newSchemaJob(reaper)
+	job.Name = getAlterKeyspaceJobName(reaper)
+
+	image := reaper.Spec.SchemaJob.AlterKeyspaceImage
+	if len(image) == 0 {
+		image = api.DefaultAlterKeyspaceImage
+	}
+
+	container := &job.Spec.Template.Spec.Containers[0]
+	container.Name = job.Name
+	container.Image = image
+	container.Command = []string{"/bin/sh", "-c", alterKeyspaceScript}
+
+	return job
+}
+
+// Creates a job that runs cqlsh to drop the keyspace. It is built like the alter keyspace job.
+func newDropKeyspaceJob(reaper *api.Reaper) *v1batch.Job {
+	job := newAlterKeyspaceJob(reaper)
+	job.Name = getDropKeyspaceJobName(reaper)
+
+	container := &job.Spec.Template.Spec.Containers[0]
+	container.Name = job.Name
+	container.Command = []string{"/bin/sh", "-c", dropKeyspaceScript}
+
+	return job
+}
+
+// Sets the arguments of cqlsh from the env vars of the schema job.
+const cqlshArgsScript = `set -e
+set -- "$CONTACT_POINTS"
+if [ -n "$USERNAME" ]; then
+  set -- "$@" -u "$USERNAME" -p "$PASSWORD"
+fi
+if [ "$TLS_ENABLED" = "true" ]; then
+  export SSL_CERTFILE="$TLS_CA_CERT"
+  set -- "$@" --ssl
+fi
+`
+
+const alterKeyspaceScript = cqlshArgsScript + `exec cqlsh "$@" -e "ALTER KEYSPACE $KEYSPACE WITH replication = $REPLICATION"
+`
+
+const dropKeyspaceScript = cqlshArgsScript + `exec cqlsh "$@" -e "DROP KEYSPACE IF EXISTS $KEYSPACE"
+`
+
 func newSchemaJob(reaper *api.Reaper) *v1batch.Job {
 	cassandra := *reaper.Spec.ServerConfig.CassandraBackend
+	envVars := []corev1.EnvVar{
+		{
+			Name:  "KEYSPACE",
+			Value: cassandra.Keyspace,
+		},
+		{
+			Name:  "CONTACT_POINTS",
+			Value: cassandra.CassandraService,
+		},
+		{
+			Name:  "REPLICATION",
+			Value: config.ReplicationToString(cassandra.Replication),
+		},
+	}
+	if authProvider := cassandra.AuthProvider; authProvider.SecretRef.Name == "" && authProvider.Username != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: "USERNAME", Value: authProvider.Username}, corev1.EnvVar{Name: "PASSWORD", Value: authProvider.Password})
+	}
+
+	jobSpec := reaper.Spec.SchemaJob
+	image := jobSpec.Image
+	if len(image) == 0 {
+		image = api.DefaultSchemaJobImage
+	}
+	imagePullPolicy := jobSpec.ImagePullPolicy
+	if len(imagePullPolicy) == 0 {
+		imagePullPolicy = api.DefaultSchemaJobImagePullPolicy
+	}
+	backoffLimit := int32(api.DefaultSchemaJobBackoffLimit)
+	if jobSpec.BackoffLimit != nil {
+		backoffLimit = *jobSpec.BackoffLimit
+	}
+
 	return &v1batch.Job{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Job",
 			APIVersion: "batch/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: reaper.Namespace,
-			Name:      getSchemaJobName(reaper),
-			Labels:    createLabels(reaper),
+			Namespace:   reaper.Namespace,
+			Name:        getSchemaJobName(reaper),
+			Labels:      createLabels(reaper),
+			Annotations: map[string]string{schemaHashAnnotation: getSchemaHash(reaper)},
 		},
 		Spec: v1batch.JobSpec{
+			BackoffLimit: &backoffLimit,
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					RestartPolicy: corev1.RestartPolicyOnFailure,
+					// Failed pods are kept so that their termination messages can be reported.
+					RestartPolicy: corev1.RestartPolicyNever,
 					Containers: []corev1.Container{
 						{
-							Name:            getSchemaJobName(reaper),
-							Image:           schemaJobImage,
-							ImagePullPolicy: schemaJobImagePullPolicy,
-							Env: []corev1.EnvVar{
-								{
-									Name:  "KEYSPACE",
-									Value: cassandra.Keyspace,
-								},
-								{
-									Name:  "CONTACT_POINTS",
-									Value: cassandra.CassandraService,
-								},
-								{
-									Name:  "REPLICATION",
-									Value: config.ReplicationToString(cassandra.Replication),
-								},
-							},
+							Name:                     getSchemaJobName(reaper),
+							Image:                    image,
+							ImagePullPolicy:          imagePullPolicy,
+							Env:                      envVars,
+							Resources:                *jobSpec.Resources.DeepCopy(),
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						},
 					},
 				},
@@ -245,6 +716,39 @@ func newSchemaJob(reaper *api.Reaper) *v1batch.Job {
 	}
 }
 
+// Mounts the TLS secret into the schema job and configures the job to connect with the PEM
+// encoded certificates.
+func addSchemaJobTLS(job *v1batch.Job, secret *corev1.Secret) {
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, newTLSVolume(secret.Name))
+
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, newTLSVolumeMount())
+	container.Env = append(container.Env, []corev1.EnvVar{
+		{
+			Name:  "TLS_ENABLED",
+			Value: "true",
+		},
+		{
+			Name:  "TLS_CA_CERT",
+			Value: path.Join(tlsMountPath, CACertKey),
+		},
+	}...)
+
+	if _, ok := secret.Data[TLSCertKey]; ok {
+		container.Env = append(container.Env, []corev1.EnvVar{
+			{
+				Name:  "TLS_CERT",
+				Value: path.Join(tlsMountPath, TLSCertKey),
+			},
+			{
+				Name:  "TLS_KEY",
+				Value: path.Join(tlsMountPath, TLSKeyKey),
+			},
+		}...)
+	}
+}
+
 func jobFinished(job *v1batch.Job) bool {
 	for _, c := range job.Status.Conditions {
 		if (c.Type == v1batch.JobComplete || c.Type == v1batch.JobFailed) && c.Status == corev1.ConditionTrue {
@@ -269,75 +773,279 @@ func (r *defaultReconciler) ReconcileDeployment(ctx context.Context, req ReaperR
 
 	req.Logger.Info("reconciling deployment", "deployment", key)
 
-	deployment := &appsv1.Deployment{}
-	desiredDeployment, err := r.buildNewDeployment(req)
+	// The Reaper instances run as sidecars of the Cassandra pods.
+	if reaper.Spec.ServerConfig.DatacenterAvailability == api.DatacenterAvailabilitySidecar {
+		if err := r.deleteStaleDeployments(ctx, req, nil); err != nil {
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		return r.reconcileSidecarStatus(ctx, req)
+	}
+
+	desiredDeployments, err := r.buildNewDeployments(req)
 	if err != nil {
 		req.Logger.Error(err, "failed to build deployment", "deployment", key)
+		r.setCondition(ctx, req, api.DeploymentAvailable, corev1.ConditionFalse, status.DeploymentBuildFailedReason, err.Error())
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+
+	if err = r.deleteStaleDeployments(ctx, req, desiredDeployments); err != nil {
 		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
 	}
 
-	err = r.Get(ctx, key, deployment)
+	ready := true
+	readyReplicas := int32(0)
+	replicas := int32(0)
+
+	for _, desiredDeployment := range desiredDeployments {
+		deployment, result, err := r.reconcileDeployment(ctx, req, desiredDeployment)
+		if result != nil {
+			return result, err
+		}
+
+		if !isDeploymentReady(deployment) {
+			req.Logger.Info("deployment not ready", "deployment", types.NamespacedName{Namespace: deployment.Namespace, Name: deployment.Name})
+			ready = false
+		}
+		readyReplicas += deployment.Status.ReadyReplicas
+		replicas += deployment.Status.Replicas
+	}
+
+	if ready {
+		if err := req.StatusManager.SetCondition(ctx, reaper, api.DeploymentAvailable, corev1.ConditionTrue, status.DeploymentReadyReason, ""); err != nil {
+			req.Logger.Error(err, "failed to update status")
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		if err := req.StatusManager.SetReady(ctx, reaper); err == nil {
+			return nil, nil
+		} else {
+			req.Logger.Error(err, "failed to update status")
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+	} else {
+		message := fmt.Sprintf("%d of %d replicas are ready", readyReplicas, replicas)
+		r.setCondition(ctx, req, api.DeploymentAvailable, corev1.ConditionFalse, status.DeploymentNotReadyReason, message)
+		if err := req.StatusManager.SetNotReady(ctx, reaper); err != nil {
+			req.Logger.Error(err, "deployment is not ready, failed to update reaper status", "deployment", key)
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		// The deployments are owned by the Reaper, so their status changes trigger reconciles.
+		return &ctrl.Result{}, nil
+	}
+}
+
+// Creates or updates the deployment. The current deployment is returned when it is up to date,
+// otherwise a non-nil result is returned.
+func (r *defaultReconciler) reconcileDeployment(ctx context.Context, req ReaperRequest, desiredDeployment *appsv1.Deployment) (*appsv1.Deployment, *ctrl.Result, error) {
+	reaper := req.Reaper
+	key := types.NamespacedName{Namespace: desiredDeployment.Namespace, Name: desiredDeployment.Name}
+
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, key, deployment)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			if err = controllerutil.SetControllerReference(reaper, desiredDeployment, r.scheme); err != nil {
 				req.Logger.Error(err, "failed to set owner on deployment", "deployment", key)
-				return &ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
+				return nil, &ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
 			}
 
 			if err = r.Create(ctx, desiredDeployment); err != nil {
 				req.Logger.Error(err, "failed to create deployment", "deployment", key)
+				r.recorder.Eventf(reaper, corev1.EventTypeWarning, events.CreateFailedReason, "failed to create deployment %s: %s", key.Name, err)
+				return nil, &ctrl.Result{RequeueAfter: 10 * time.Second}, err
 			}
-			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+			r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.CreatedReason, "created deployment %s", key.Name)
+			return nil, &ctrl.Result{}, nil
 		} else {
 			req.Logger.Error(err, "failed to get deployment", "deployment", key)
-			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+			return nil, &ctrl.Result{RequeueAfter: 10 * time.Second}, err
 		}
-	} else {
-		if !util.ResourcesHaveSameHash(desiredDeployment, deployment) {
-			req.Logger.Info("updating deployment", "deployment", key)
-
-			// TODO Figure out how we want to handle any deployment template spec updates and intelligently copy them.
-			// Note that simply calling Deployment.DeepCopy() will fail on update because the
-			// label selector is immutable.
+	}
 
-			// TODO add unit/integration tests
+	if !util.ResourcesHaveSameHash(desiredDeployment, deployment) {
+		req.Logger.Info("updating deployment", "deployment", key)
+
+		// TODO Figure out how we want to handle any deployment template spec updates and intelligently copy them.
+		// Note that simply calling Deployment.DeepCopy() will fail on update because the
+		// label selector is immutable.
+
+		// TODO add unit/integration tests
+
+		desiredDeployment.Labels = util.MergeMap(map[string]string{}, deployment.Labels, desiredDeployment.Labels)
+		desiredDeployment.Annotations = util.MergeMap(map[string]string{}, deployment.Annotations, desiredDeployment.Annotations)
+
+		deployment.Labels = desiredDeployment.Labels
+		deployment.Annotations = desiredDeployment.Annotations
+
+		deployment.Spec.Template.Labels = desiredDeployment.Spec.Template.Labels
+		deployment.Spec.Template.Annotations = desiredDeployment.Spec.Template.Annotations
+		deployment.Spec.Template.Spec.Containers = desiredDeployment.Spec.Template.Spec.Containers
+		deployment.Spec.Template.Spec.Volumes = desiredDeployment.Spec.Template.Spec.Volumes
+		deployment.Spec.Template.Spec.Affinity = desiredDeployment.Spec.Template.Spec.Affinity
+		deployment.Spec.Template.Spec.Tolerations = desiredDeployment.Spec.Template.Spec.Tolerations
+		deployment.Spec.Template.Spec.NodeSelector = desiredDeployment.Spec.Template.Spec.NodeSelector
+		deployment.Spec.Template.Spec.PriorityClassName = desiredDeployment.Spec.Template.Spec.PriorityClassName
+		deployment.Spec.Template.Spec.SecurityContext = desiredDeployment.Spec.Template.Spec.SecurityContext
+		deployment.Spec.Template.Spec.ImagePullSecrets = desiredDeployment.Spec.Template.Spec.ImagePullSecrets
+		deployment.Spec.Template.Spec.ServiceAccountName = desiredDeployment.Spec.Template.Spec.ServiceAccountName
+
+		deployment.Spec.Replicas = desiredDeployment.Spec.Replicas
+		deployment.Spec.MinReadySeconds = desiredDeployment.Spec.MinReadySeconds
+		deployment.Spec.Paused = desiredDeployment.Spec.Paused
+		deployment.Spec.ProgressDeadlineSeconds = desiredDeployment.Spec.ProgressDeadlineSeconds
+		deployment.Spec.RevisionHistoryLimit = desiredDeployment.Spec.RevisionHistoryLimit
+		deployment.Spec.Strategy = desiredDeployment.Spec.Strategy
+
+		if err = r.Update(ctx, deployment); err != nil {
+			req.Logger.Error(err, "failed to update deployment", "deployment", deployment)
+			r.recorder.Eventf(reaper, corev1.EventTypeWarning, events.UpdateFailedReason, "failed to update deployment %s: %s", key.Name, err)
+			return nil, &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		}
+		r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.UpdatedReason, "updated deployment %s", key.Name)
+		return nil, &ctrl.Result{}, nil
+	}
 
-			desiredDeployment.Labels = util.MergeMap(map[string]string{}, deployment.Labels, desiredDeployment.Labels)
-			desiredDeployment.Annotations = util.MergeMap(map[string]string{}, deployment.Annotations, desiredDeployment.Annotations)
+	return deployment, nil, nil
+}
 
-			deployment.Labels = desiredDeployment.Labels
-			deployment.Annotations = desiredDeployment.Annotations
+// Deletes the deployments of the Reaper that are no longer desired, e.g., the deployment of a
+// data center that was removed or the single deployment after switching to one deployment per
+// data center.
+func (r *defaultReconciler) deleteStaleDeployments(ctx context.Context, req ReaperRequest, desiredDeployments []*appsv1.Deployment) error {
+	reaper := req.Reaper
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(reaper.Namespace), client.MatchingLabels(createLabels(reaper))); err != nil {
+		req.Logger.Error(err, "failed to list deployments")
+		return err
+	}
 
-			deployment.Spec.Template.Labels = desiredDeployment.Spec.Template.Labels
-			deployment.Spec.Template.Annotations = desiredDeployment.Spec.Template.Annotations
-			deployment.Spec.Template.Spec.Containers = desiredDeployment.Spec.Template.Spec.Containers
+	desired := make(map[string]bool)
+	for _, deployment := range desiredDeployments {
+		desired[deployment.Name] = true
+	}
 
-			deployment.Spec.MinReadySeconds = desiredDeployment.Spec.MinReadySeconds
-			deployment.Spec.Paused = desiredDeployment.Spec.Paused
-			deployment.Spec.ProgressDeadlineSeconds = desiredDeployment.Spec.ProgressDeadlineSeconds
-			deployment.Spec.RevisionHistoryLimit = desiredDeployment.Spec.RevisionHistoryLimit
-			deployment.Spec.Strategy = desiredDeployment.Spec.Strategy
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if desired[deployment.Name] || !metav1.IsControlledBy(deployment, reaper) {
+			continue
+		}
 
-			if err = r.Update(ctx, deployment); err != nil {
-				req.Logger.Error(err, "failed to update deployment", "deployment", deployment)
-			}
-			return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+		req.Logger.Info("deleting deployment", "deployment", types.NamespacedName{Namespace: deployment.Namespace, Name: deployment.Name})
+		if err := r.Delete(ctx, deployment); err != nil && !errors.IsNotFound(err) {
+			req.Logger.Error(err, "failed to delete deployment", "deployment", deployment.Name)
+			r.recorder.Eventf(reaper, corev1.EventTypeWarning, events.DeleteFailedReason, "failed to delete deployment %s: %s", deployment.Name, err)
+			return err
 		}
+		r.recorder.Eventf(reaper, corev1.EventTypeNormal, events.DeletedReason, "deleted deployment %s", deployment.Name)
+	}
 
-		if isDeploymentReady(deployment) {
-			if err := req.StatusManager.SetReady(ctx, reaper); err == nil {
-				return nil, nil
-			} else {
-				req.Logger.Error(err, "failed to update status")
-				return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
-			}
-		} else {
-			req.Logger.Info("deployment not ready", "deployment", key)
-			if err := req.StatusManager.SetNotReady(ctx, reaper); err != nil {
-				req.Logger.Error(err, "deployment is not ready, failed to update reaper status", "deployment", key)
-				return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
-			}
-			return &ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	return nil
+}
+
+// Returns a deployment for each data center when DatacenterAvailability is EACH, otherwise the
+// single deployment of the Reaper.
+func (r *defaultReconciler) buildNewDeployments(req ReaperRequest) ([]*appsv1.Deployment, error) {
+	deployment, err := r.buildNewDeployment(req)
+	if err != nil {
+		return nil, err
+	}
+
+	dcs := getDatacenters(req.Reaper)
+	if len(dcs) == 0 {
+		util.AddHashAnnotation(deployment)
+		return []*appsv1.Deployment{deployment}, nil
+	}
+
+	deployments := make([]*appsv1.Deployment, 0, len(dcs))
+	for _, dc := range dcs {
+		dcDeployment := deployment.DeepCopy()
+		setDeploymentDatacenter(dcDeployment, req.Reaper, dc)
+		util.AddHashAnnotation(dcDeployment)
+		deployments = append(deployments, dcDeployment)
+	}
+
+	return deployments, nil
+}
+
+// Returns the sorted data centers in which a Reaper instance runs when DatacenterAvailability
+// is EACH. These are the data centers of the backend keyspace's replication.
+func getDatacenters(reaper *api.Reaper) []string {
+	cfg := reaper.Spec.ServerConfig
+	if cfg.DatacenterAvailability != api.DatacenterAvailabilityEach || cfg.CassandraBackend == nil ||
+		cfg.CassandraBackend.Replication.NetworkTopologyStrategy == nil {
+		return nil
+	}
+
+	dcs := make([]string, 0)
+	for dc := range *cfg.CassandraBackend.Replication.NetworkTopologyStrategy {
+		dcs = append(dcs, dc)
+	}
+	sort.Strings(dcs)
+
+	return dcs
+}
+
+func getDatacenterDeploymentName(reaperName, dc string) string {
+	return fmt.Sprintf("%s-%s", reaperName, strings.ReplaceAll(strings.ToLower(dc), "_", "-"))
+}
+
+// Returns the contact points of the Reaper instance of the data center. When the backend is
+// derived from a CassandraDatacenter, these are the service of the data center, which
+// cass-operator names after the cluster and the data center in the namespace of the referenced
+// one. Otherwise the configured service is used for every data center.
+func getDatacenterContactPoints(reaper *api.Reaper, dc string) string {
+	backend := reaper.Spec.ServerConfig.CassandraBackend
+	if backend.CassandraDatacenterRef == nil {
+		return backend.CassandraService
+	}
+
+	cassdc := &cassdcv1beta1.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: dc},
+		Spec:       cassdcv1beta1.CassandraDatacenterSpec{ClusterName: backend.ClusterName},
+	}
+	service := cassdc.GetDatacenterServiceName()
+	if namespace := backend.CassandraDatacenterRef.Namespace; len(namespace) > 0 && namespace != reaper.Namespace {
+		service += "." + namespace
+	}
+	return service
+}
+
+// Turns the deployment into the one of the data center. The pods are labeled with the data center
+// and, unless the pod template sets an affinity, prefer to run in the region of the data center's
+// Cassandra pods. Reaper is configured with the data center as its local one and connects to the
+// backend through it.
+func setDeploymentDatacenter(deployment *appsv1.Deployment, reaper *api.Reaper, dc string) {
+	container := &deployment.Spec.Template.Spec.Containers[0]
+	for i := range container.Env {
+		if container.Env[i].Name == "REAPER_CASS_CONTACT_POINTS" {
+			container.Env[i].Value = fmt.Sprintf("[%s]", getDatacenterContactPoints(reaper, dc))
+		}
+	}
+	container.Env = append(container.Env, corev1.EnvVar{Name: "REAPER_CASS_LOCAL_DC", Value: dc})
+
+	deployment.Name = getDatacenterDeploymentName(deployment.Name, dc)
+	deployment.Labels[mlabels.DatacenterLabel] = dc
+	deployment.Spec.Template.Labels[mlabels.DatacenterLabel] = dc
+	deployment.Spec.Selector.MatchExpressions = append(deployment.Spec.Selector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      mlabels.DatacenterLabel,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{dc},
+	})
+
+	if deployment.Spec.Template.Spec.Affinity == nil {
+		deployment.Spec.Template.Spec.Affinity = &corev1.Affinity{
+			PodAffinity: &corev1.PodAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					{
+						Weight: 100,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{cassdcv1beta1.DatacenterLabel: dc},
+							},
+							TopologyKey: corev1.LabelZoneRegionStable,
+						},
+					},
+				},
+			},
 		}
 	}
 }
@@ -351,23 +1059,170 @@ func (r *defaultReconciler) buildNewDeployment(req ReaperRequest) (*appsv1.Deplo
 		secret, err := r.getSecret(types.NamespacedName{Namespace: reaper.Namespace, Name: reaper.Spec.ServerConfig.JmxUserSecretName})
 		if err != nil {
 			req.Logger.Error(err, "failed to get jmxUserSecret", "deployment", key)
-			return nil, err
+			return nil, r.secretError(reaper, fmt.Errorf("failed to get jmxUserSecret %s: %w", reaper.Spec.ServerConfig.JmxUserSecretName, err))
 		}
 
 		if usernameEnvVar, passwordEnvVar, err := r.secretsManager.GetJmxAuthCredentials(secret); err == nil {
-			addJmxAuthEnvVars(deployment, usernameEnvVar, passwordEnvVar)
+			addAuthEnvVars(deployment, usernameEnvVar, passwordEnvVar)
 		} else {
 			req.Logger.Error(err, "failed to get JMX credentials", "deployment", key)
-			return nil, err
+			return nil, r.secretError(reaper, err)
+		}
+	}
+
+	if usernameEnvVar, passwordEnvVar, err := r.getCassandraAuthCredentials(reaper); err != nil {
+		req.Logger.Error(err, "failed to get cassandra credentials", "deployment", key)
+		return nil, r.secretError(reaper, err)
+	} else if usernameEnvVar != nil {
+		addAuthEnvVars(deployment, usernameEnvVar, passwordEnvVar)
+	}
+
+	if uiAuth := reaper.Spec.UIAuth; uiAuth != nil {
+		secret, err := r.getSecret(types.NamespacedName{Namespace: reaper.Namespace, Name: uiAuth.SecretRef.Name})
+		if err != nil {
+			req.Logger.Error(err, "failed to get ui auth secret", "deployment", key)
+			return nil, r.secretError(reaper, fmt.Errorf("failed to get ui auth secret %s: %w", uiAuth.SecretRef.Name, err))
+		}
+
+		if usernameEnvVar, passwordEnvVar, err := r.secretsManager.GetUIAuthCredentials(secret); err == nil {
+			addAuthEnvVars(deployment, usernameEnvVar, passwordEnvVar)
+		} else {
+			req.Logger.Error(err, "failed to get ui auth credentials", "deployment", key)
+			return nil, r.secretError(reaper, err)
+		}
+	}
+
+	if tls := reaper.Spec.ServerConfig.TLS; tls != nil {
+		secret, err := r.getTLSSecret(reaper, false)
+		if err != nil {
+			req.Logger.Error(err, "failed to get tls secret", "deployment", key)
+			return nil, r.secretError(reaper, err)
 		}
+		addDeploymentTLS(deployment, tls, secret)
 	}
 
-	util.AddHashAnnotation(deployment)
+	if hash, err := r.getSecretsHash(reaper); err != nil {
+		req.Logger.Error(err, "failed to compute secrets hash", "deployment", key)
+		return nil, r.secretError(reaper, err)
+	} else if len(hash) > 0 {
+		template := &deployment.Spec.Template
+		template.Annotations = util.MergeMap(map[string]string{}, template.Annotations, map[string]string{secretsHashAnnotation: hash})
+	}
 
 	return deployment, nil
 }
 
-func addJmxAuthEnvVars(deployment *appsv1.Deployment, usernameEnvVar, passwordEnvVar *corev1.EnvVar) {
+// Records a warning event for the error of a secret that the Reaper references and returns the
+// error.
+func (r *defaultReconciler) secretError(reaper *api.Reaper, err error) error {
+	r.recorder.Event(reaper, corev1.EventTypeWarning, events.SecretErrorReason, err.Error())
+	return err
+}
+
+// Returns the env vars for the Cassandra backend credentials if the auth provider references a
+// secret. Nil env vars are returned when it does not.
+func (r *defaultReconciler) getCassandraAuthCredentials(reaper *api.Reaper) (*corev1.EnvVar, *corev1.EnvVar, error) {
+	cassandra := reaper.Spec.ServerConfig.CassandraBackend
+	if cassandra == nil || cassandra.AuthProvider.SecretRef.Name == "" {
+		return nil, nil, nil
+	}
+
+	secretName := cassandra.AuthProvider.SecretRef.Name
+	secret, err := r.getSecret(types.NamespacedName{Namespace: reaper.Namespace, Name: secretName})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cassandra auth secret %s: %w", secretName, err)
+	}
+
+	return r.secretsManager.GetCassandraAuthCredentials(secret)
+}
+
+// Returns the TLS secret after checking that it has the required keys.
+func (r *defaultReconciler) getTLSSecret(reaper *api.Reaper, requireCACert bool) (*corev1.Secret, error) {
+	secretName := reaper.Spec.ServerConfig.TLS.SecretRef.Name
+	secret, err := r.getSecret(types.NamespacedName{Namespace: reaper.Namespace, Name: secretName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tls secret %s: %w", secretName, err)
+	}
+
+	if err = r.secretsManager.ValidateTLSSecret(secret, requireCACert); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// Mounts the TLS secret into the Reaper pod and configures the JVM with the trust store, and the
+// key store if the secret has one. The store passwords are read from the secret and passed to
+// the JVM through dependent env vars.
+func addDeploymentTLS(deployment *appsv1.Deployment, tls *api.TLSConfig, secret *corev1.Secret) {
+	podSpec := &deployment.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, newTLSVolume(secret.Name))
+
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, newTLSVolumeMount())
+
+	envVars := []corev1.EnvVar{newSecretKeyEnvVar("TLS_TRUSTSTORE_PASSWORD", secret.Name, TruststorePasswordKey)}
+	javaOpts := []string{
+		"-Djavax.net.ssl.trustStore=" + path.Join(tlsMountPath, TruststoreKey),
+		"-Djavax.net.ssl.trustStorePassword=$(TLS_TRUSTSTORE_PASSWORD)",
+	}
+
+	if _, ok := secret.Data[KeystoreKey]; ok {
+		envVars = append(envVars, newSecretKeyEnvVar("TLS_KEYSTORE_PASSWORD", secret.Name, KeystorePasswordKey))
+		javaOpts = append(javaOpts,
+			"-Djavax.net.ssl.keyStore="+path.Join(tlsMountPath, KeystoreKey),
+			"-Djavax.net.ssl.keyStorePassword=$(TLS_KEYSTORE_PASSWORD)")
+	}
+
+	if tls.JMXEnabled {
+		// Tells Reaper to use SSL socket factories for its JMX connections.
+		javaOpts = append(javaOpts, "-Dssl.enable=true")
+	}
+
+	// JAVA_OPTS has to come after the password env vars for them to be expanded.
+	envVars = append(envVars, corev1.EnvVar{Name: "JAVA_OPTS", Value: strings.Join(javaOpts, " ")})
+
+	if tls.CQLEnabled {
+		envVars = append(envVars, corev1.EnvVar{Name: "REAPER_CASS_NATIVE_PROTOCOL_SSL_ENCRYPTION_ENABLED", Value: "true"})
+	}
+
+	container.Env = append(container.Env, envVars...)
+}
+
+func newTLSVolume(secretName string) corev1.Volume {
+	return corev1.Volume{
+		Name: tlsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+			},
+		},
+	}
+}
+
+func newTLSVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      tlsVolumeName,
+		MountPath: tlsMountPath,
+		ReadOnly:  true,
+	}
+}
+
+func newSecretKeyEnvVar(name, secretName, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretName,
+				},
+				Key: key,
+			},
+		},
+	}
+}
+
+func addAuthEnvVars(deployment *appsv1.Deployment, usernameEnvVar, passwordEnvVar *corev1.EnvVar) {
 	envVars := deployment.Spec.Template.Spec.Containers[0].Env
 	envVars = append(envVars, *usernameEnvVar)
 	envVars = append(envVars, *passwordEnvVar)
@@ -376,6 +1231,10 @@ func addJmxAuthEnvVars(deployment *appsv1.Deployment, usernameEnvVar, passwordEn
 
 func newDeployment(reaper *api.Reaper) *appsv1.Deployment {
 	labels := createLabels(reaper)
+	podTemplate := reaper.Spec.PodTemplate
+
+	// The operator's labels are applied last since they are used in the selector.
+	podLabels := util.MergeMap(map[string]string{}, podTemplate.Labels, labels)
 
 	selector := metav1.LabelSelector{
 		MatchExpressions: []metav1.LabelSelectorRequirement{
@@ -403,28 +1262,45 @@ func newDeployment(reaper *api.Reaper) *appsv1.Deployment {
 		PeriodSeconds:       15,
 	}
 
-	envVars := make([]corev1.EnvVar, 0)
+	envVars := newServerConfigEnvVars(reaper)
 	if reaper.Spec.ServerConfig.CassandraBackend != nil {
-		envVars = []corev1.EnvVar{
+		envVars = append(envVars, []corev1.EnvVar{
 			{
 				Name:  "REAPER_STORAGE_TYPE",
 				Value: "cassandra",
 			},
-			{
-				Name:  "REAPER_ENABLE_DYNAMIC_SEED_LIST",
-				Value: "false",
-			},
 			{
 				Name:  "REAPER_CASS_CONTACT_POINTS",
 				Value: fmt.Sprintf("[%s]", reaper.Spec.ServerConfig.CassandraBackend.CassandraService),
 			},
-			{
-				Name:  "REAPER_AUTH_ENABLED",
-				Value: "false",
-			},
+		}...)
+
+		// The credentials from the secret are added when the deployment is built since the
+		// secret has to be looked up.
+		authProvider := reaper.Spec.ServerConfig.CassandraBackend.AuthProvider
+		if authProvider.SecretRef.Name != "" {
+			envVars = append(envVars, corev1.EnvVar{Name: "REAPER_CASS_AUTH_ENABLED", Value: "true"})
+		} else if authProvider.Username != "" {
+			envVars = append(envVars, []corev1.EnvVar{
+				{
+					Name:  "REAPER_CASS_AUTH_ENABLED",
+					Value: "true",
+				},
+				{
+					Name:  "REAPER_CASS_AUTH_USERNAME",
+					Value: authProvider.Username,
+				},
+				{
+					Name:  "REAPER_CASS_AUTH_PASSWORD",
+					Value: authProvider.Password,
+				},
+			}...)
 		}
 	}
 
+	// The UI credentials are added from the secret when the deployment is built.
+	envVars = append(envVars, corev1.EnvVar{Name: "REAPER_AUTH_ENABLED", Value: strconv.FormatBool(reaper.Spec.UIAuth != nil)})
+
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: reaper.Namespace,
@@ -432,12 +1308,21 @@ func newDeployment(reaper *api.Reaper) *appsv1.Deployment {
 			Labels:    labels,
 		},
 		Spec: appsv1.DeploymentSpec{
+			Replicas: reaper.Spec.Replicas,
 			Selector: &selector,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      podLabels,
+					Annotations: podTemplate.Annotations,
 				},
 				Spec: corev1.PodSpec{
+					Affinity:           podTemplate.Affinity,
+					Tolerations:        podTemplate.Tolerations,
+					NodeSelector:       podTemplate.NodeSelector,
+					PriorityClassName:  podTemplate.PriorityClassName,
+					SecurityContext:    podTemplate.PodSecurityContext,
+					ImagePullSecrets:   podTemplate.ImagePullSecrets,
+					ServiceAccountName: podTemplate.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
 							Name:            "reaper",
@@ -455,9 +1340,11 @@ func newDeployment(reaper *api.Reaper) *appsv1.Deployment {
 									Protocol:      "TCP",
 								},
 							},
-							LivenessProbe:  healthProbe,
-							ReadinessProbe: healthProbe,
-							Env:            envVars,
+							LivenessProbe:   healthProbe,
+							ReadinessProbe:  healthProbe,
+							Env:             envVars,
+							Resources:       podTemplate.Resources,
+							SecurityContext: podTemplate.SecurityContext,
 						},
 					},
 				},
@@ -466,8 +1353,71 @@ func newDeployment(reaper *api.Reaper) *appsv1.Deployment {
 	}
 }
 
+// Maps the Reaper server settings to the environment variables that the Reaper image uses to
+// generate its configuration file. Settings that are not set are left to the image defaults.
+// Because the variables are part of the pod template, changing a setting rolls the Reaper pod.
+func newServerConfigEnvVars(reaper *api.Reaper) []corev1.EnvVar {
+	cfg := reaper.Spec.ServerConfig
+	envVars := make([]corev1.EnvVar, 0)
+
+	addInt32 := func(name string, value *int32) {
+		if value != nil {
+			envVars = append(envVars, corev1.EnvVar{Name: name, Value: strconv.FormatInt(int64(*value), 10)})
+		}
+	}
+	addBool := func(name string, value *bool) {
+		if value != nil {
+			envVars = append(envVars, corev1.EnvVar{Name: name, Value: strconv.FormatBool(*value)})
+		}
+	}
+	addString := func(name, value string) {
+		if len(value) > 0 {
+			envVars = append(envVars, corev1.EnvVar{Name: name, Value: value})
+		}
+	}
+
+	addString("REAPER_DATACENTER_AVAILABILITY", string(cfg.DatacenterAvailability))
+	addInt32("REAPER_HANGING_REPAIR_TIMEOUT_MINS", cfg.HangingRepairTimeoutMins)
+	addBool("REAPER_INCREMENTAL_REPAIR", &cfg.IncrementalRepair)
+	addString("REAPER_REPAIR_INTENSITY", cfg.RepairIntensity)
+	// The name is misspelled in the Reaper image.
+	addString("REAPER_REPAIR_PARALELLISM", cfg.RepairParallelism)
+	addInt32("REAPER_REPAIR_RUN_THREADS", cfg.RepairRunThreadCount)
+	addInt32("REAPER_SCHEDULE_DAYS_BETWEEN", cfg.ScheduleDaysBetween)
+	addBool("REAPER_ENABLE_CROSS_ORIGIN", cfg.EnableCrossOrigin)
+	enableDynamicSeedList := cfg.EnableDynamicSeedList
+	if enableDynamicSeedList == nil {
+		// In distributed mode the instances need to discover all of the nodes of a cluster
+		// rather than only the seed that the cluster was registered with. This is derived
+		// here rather than defaulted in the spec so that it follows changes to the replicas.
+		distributed := reaper.Spec.Replicas != nil && *reaper.Spec.Replicas > 1
+		enableDynamicSeedList = &distributed
+	}
+	addBool("REAPER_ENABLE_DYNAMIC_SEED_LIST", enableDynamicSeedList)
+	addBool("REAPER_BLACKLIST_TWCS", &cfg.BlacklistTwcsTables)
+	addInt32("REAPER_JMX_CONNECTION_TIMEOUT_IN_SECONDS", cfg.JmxConnectionTimeoutInSeconds)
+	addInt32("REAPER_SEGMENT_COUNT_PER_NODE", cfg.SegmentCountPerNode)
+
+	if autoScheduling := cfg.AutoScheduling; autoScheduling.Enabled {
+		addBool("REAPER_AUTO_SCHEDULING_ENABLED", &autoScheduling.Enabled)
+		addString("REAPER_AUTO_SCHEDULING_INITIAL_DELAY_PERIOD", autoScheduling.InitialDelayPeriod)
+		addString("REAPER_AUTO_SCHEDULING_PERIOD_BETWEEN_POLLS", autoScheduling.PeriodBetweenPolls)
+		addString("REAPER_AUTO_SCHEDULING_TIME_BEFORE_FIRST_SCHEDULE", autoScheduling.TimeBeforeFirstSchedule)
+		addString("REAPER_AUTO_SCHEDULING_SCHEDULE_SPREAD_PERIOD", autoScheduling.ScheduleSpreadPeriod)
+		if len(autoScheduling.ExcludedKeyspaces) > 0 {
+			addString("REAPER_AUTO_SCHEDULING_EXCLUDED_KEYSPACES", fmt.Sprintf("[%s]", strings.Join(autoScheduling.ExcludedKeyspaces, ", ")))
+		}
+	}
+
+	return envVars
+}
+
 func isDeploymentReady(deployment *appsv1.Deployment) bool {
-	return deployment.Status.ReadyReplicas == 1
+	desiredReplicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desiredReplicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ReadyReplicas >= desiredReplicas
 }
 
 func createLabels(r *api.Reaper) map[string]string {
@@ -478,6 +1428,26 @@ func createLabels(r *api.Reaper) map[string]string {
 	return labels
 }
 
+// Returns a hash of the resource versions of the secrets that the Reaper references or an empty
+// string if it does not reference any.
+func (r *defaultReconciler) getSecretsHash(reaper *api.Reaper) (string, error) {
+	names := GetSecretNames(reaper)
+	if len(names) == 0 {
+		return "", nil
+	}
+
+	versions := make(map[string]string)
+	for _, name := range names {
+		secret, err := r.getSecret(types.NamespacedName{Namespace: reaper.Namespace, Name: name})
+		if err != nil {
+			return "", fmt.Errorf("failed to get secret %s: %w", name, err)
+		}
+		versions[name] = secret.ResourceVersion
+	}
+
+	return util.DeepHashString(versions), nil
+}
+
 func (r *defaultReconciler) getSecret(key types.NamespacedName) (*corev1.Secret, error) {
 	secret := &corev1.Secret{}
 	err := r.Get(context.Background(), key, secret)
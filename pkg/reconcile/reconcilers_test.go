@@ -1,16 +1,26 @@
 package reconcile
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
 	"github.com/thelastpickle/reaper-operator/pkg/config"
 	mlabels "github.com/thelastpickle/reaper-operator/pkg/labels"
+	"github.com/thelastpickle/reaper-operator/pkg/status"
+	"github.com/thelastpickle/reaper-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestNewService(t *testing.T) {
@@ -36,6 +46,96 @@ func TestNewService(t *testing.T) {
 		},
 	}
 	assert.Equal(t, port, service.Spec.Ports[0])
+	assert.Equal(t, corev1.ServiceTypeClusterIP, service.Spec.Type)
+}
+
+func TestNewServiceWithOptions(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.Service = api.ReaperService{
+		Type:            corev1.ServiceTypeLoadBalancer,
+		Annotations:     map[string]string{"cloud.google.com/load-balancer-type": "Internal"},
+		ExposeAdminPort: true,
+	}
+	key := types.NamespacedName{Namespace: reaper.Namespace, Name: GetServiceName(reaper.Name)}
+
+	service := newService(key, reaper)
+
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, service.Spec.Type)
+	assert.Equal(t, reaper.Spec.Service.Annotations, service.Annotations)
+	assert.Equal(t, 2, len(service.Spec.Ports))
+	assert.Equal(t, "admin", service.Spec.Ports[1].Name)
+	assert.Equal(t, int32(8081), service.Spec.Ports[1].Port)
+}
+
+func TestUpdateServiceSpec(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.Service.Type = corev1.ServiceTypeNodePort
+	key := types.NamespacedName{Namespace: reaper.Namespace, Name: GetServiceName(reaper.Name)}
+
+	current := corev1.ServiceSpec{
+		Type:      corev1.ServiceTypeNodePort,
+		ClusterIP: "10.0.0.1",
+		Ports:     []corev1.ServicePort{{Name: "app", Port: 9090, NodePort: 30080}},
+		Selector:  map[string]string{"app": "other"},
+	}
+	desired := newService(key, reaper).Spec
+
+	updateServiceSpec(&current, desired)
+
+	assert.Equal(t, "10.0.0.1", current.ClusterIP)
+	assert.Equal(t, desired.Selector, current.Selector)
+	assert.Equal(t, int32(8080), current.Ports[0].Port)
+	assert.Equal(t, int32(30080), current.Ports[0].NodePort, "the allocated node port should be kept")
+
+	// Node ports have to be cleared when switching to ClusterIP.
+	updateServiceSpec(&current, newService(key, newReaperWithCassandraBackend()).Spec)
+	assert.Equal(t, corev1.ServiceTypeClusterIP, current.Type)
+	assert.Equal(t, int32(0), current.Ports[0].NodePort)
+}
+
+func TestReconcileServiceUpdatesDriftedSpec(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	key := types.NamespacedName{Namespace: reaper.Namespace, Name: GetServiceName(reaper.Name)}
+
+	// The live service was edited but its hash annotation still matches the desired one.
+	live := newService(key, reaper)
+	util.AddHashAnnotation(live)
+	live.Spec.Ports = []corev1.ServicePort{{Name: "app", Port: 9090, Protocol: corev1.ProtocolTCP}}
+	live.Spec.Selector = map[string]string{"app": "other"}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = api.AddToScheme(scheme)
+	c := fake.NewFakeClientWithScheme(scheme, reaper, live)
+	r := &defaultReconciler{Client: c, scheme: scheme, recorder: record.NewFakeRecorder(10)}
+
+	req := ReaperRequest{Reaper: reaper, Logger: ctrl.Log.WithName("test"), StatusManager: &status.StatusManager{Client: c}}
+	result, err := r.ReconcileService(context.Background(), req)
+	assert.NoError(t, err)
+	assert.True(t, result == nil, "expected the reconciliation to continue")
+
+	service := &corev1.Service{}
+	assert.NoError(t, c.Get(context.Background(), key, service))
+
+	desired := newService(key, reaper)
+	assert.Equal(t, desired.Spec.Ports, service.Spec.Ports)
+	assert.Equal(t, desired.Spec.Selector, service.Spec.Selector)
+}
+
+func TestServiceSpecChanged(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.Service.Type = corev1.ServiceTypeNodePort
+	key := types.NamespacedName{Namespace: reaper.Namespace, Name: GetServiceName(reaper.Name)}
+	desired := newService(key, reaper).Spec
+
+	// Allocated node ports do not count as a change.
+	current := *desired.DeepCopy()
+	current.ClusterIP = "10.0.0.1"
+	current.Ports[0].NodePort = 30080
+	assert.False(t, serviceSpecChanged(current, desired))
+
+	current.Type = corev1.ServiceTypeClusterIP
+	assert.True(t, serviceSpecChanged(current, desired))
 }
 
 func TestNewSchemaJob(t *testing.T) {
@@ -48,12 +148,14 @@ func TestNewSchemaJob(t *testing.T) {
 	assert.Equal(t, createLabels(reaper), job.Labels)
 
 	podSpec := job.Spec.Template.Spec
-	assert.Equal(t, corev1.RestartPolicyOnFailure, podSpec.RestartPolicy)
+	assert.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	assert.Equal(t, int32(api.DefaultSchemaJobBackoffLimit), *job.Spec.BackoffLimit)
 	assert.Equal(t, 1, len(podSpec.Containers))
 
 	container := podSpec.Containers[0]
-	assert.Equal(t, schemaJobImage, container.Image)
-	assert.Equal(t, schemaJobImagePullPolicy, container.ImagePullPolicy)
+	assert.Equal(t, api.DefaultSchemaJobImage, container.Image)
+	assert.Equal(t, api.DefaultSchemaJobImagePullPolicy, container.ImagePullPolicy)
+	assert.Equal(t, corev1.TerminationMessageFallbackToLogsOnError, container.TerminationMessagePolicy)
 	assert.ElementsMatch(t, container.Env, []corev1.EnvVar{
 		{
 			Name:  "KEYSPACE",
@@ -70,6 +172,95 @@ func TestNewSchemaJob(t *testing.T) {
 	})
 }
 
+func TestNewSchemaJobWithOptions(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	backoffLimit := int32(1)
+	reaper.Spec.SchemaJob = api.ReaperSchemaJob{
+		Image:           "test/create_keyspace:1.0.0",
+		ImagePullPolicy: corev1.PullAlways,
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+		},
+		BackoffLimit: &backoffLimit,
+	}
+
+	job := newSchemaJob(reaper)
+
+	assert.Equal(t, backoffLimit, *job.Spec.BackoffLimit)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, reaper.Spec.SchemaJob.Image, container.Image)
+	assert.Equal(t, corev1.PullAlways, container.ImagePullPolicy)
+	assert.Equal(t, reaper.Spec.SchemaJob.Resources, container.Resources)
+}
+
+func TestGetSchemaJobRetryDelay(t *testing.T) {
+	assert.Equal(t, schemaJobRetryDelay, getSchemaJobRetryDelay(0))
+	assert.Equal(t, 4*schemaJobRetryDelay, getSchemaJobRetryDelay(2))
+	assert.Equal(t, maxSchemaJobRetryDelay, getSchemaJobRetryDelay(10))
+}
+
+func TestSchemaHash(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	job := newSchemaJob(reaper)
+	assert.True(t, jobHasSchemaHash(job, reaper))
+
+	replication := map[string]int32{"DC1": 3, "DC2": 3}
+	reaper.Spec.ServerConfig.CassandraBackend.Replication.NetworkTopologyStrategy = &replication
+	assert.False(t, jobHasSchemaHash(job, reaper))
+}
+
+func TestNewAlterKeyspaceJob(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+
+	job := newAlterKeyspaceJob(reaper)
+
+	assert.Equal(t, getAlterKeyspaceJobName(reaper), job.Name)
+	assert.True(t, jobHasSchemaHash(job, reaper))
+
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, api.DefaultAlterKeyspaceImage, container.Image)
+	assert.Equal(t, []string{"/bin/sh", "-c", alterKeyspaceScript}, container.Command)
+	assert.Contains(t, container.Env, corev1.EnvVar{
+		Name:  "REPLICATION",
+		Value: config.ReplicationToString(reaper.Spec.ServerConfig.CassandraBackend.Replication),
+	})
+}
+
+func TestNewDropKeyspaceJob(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+
+	job := newDropKeyspaceJob(reaper)
+
+	assert.Equal(t, getDropKeyspaceJobName(reaper), job.Name)
+
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, job.Name, container.Name)
+	assert.Equal(t, api.DefaultAlterKeyspaceImage, container.Image)
+	assert.Equal(t, []string{"/bin/sh", "-c", dropKeyspaceScript}, container.Command)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "KEYSPACE", Value: reaper.Spec.ServerConfig.CassandraBackend.Keyspace})
+}
+
+func TestNewSchemaJobWithLegacyCredentials(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.ServerConfig.CassandraBackend.AuthProvider = api.AuthProvider{
+		Type:     api.DefaultAuthProviderType,
+		Username: "reaper",
+		Password: "secret",
+	}
+
+	job := newSchemaJob(reaper)
+
+	env := job.Spec.Template.Spec.Containers[0].Env
+	assert.Contains(t, env, corev1.EnvVar{Name: "USERNAME", Value: "reaper"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "PASSWORD", Value: "secret"})
+
+	// Credentials from a secret are added when the job is created.
+	reaper.Spec.ServerConfig.CassandraBackend.AuthProvider.SecretRef.Name = "reaper-cql"
+	job = newSchemaJob(reaper)
+
+	assert.Equal(t, 3, len(job.Spec.Template.Spec.Containers[0].Env))
+}
+
 func TestNewDeployment(t *testing.T) {
 	image := "test/reaper:latest"
 	reaper := newReaperWithCassandraBackend()
@@ -106,13 +297,21 @@ func TestNewDeployment(t *testing.T) {
 	assert.Equal(t, image, container.Image)
 	assert.ElementsMatch(t, container.Env, []corev1.EnvVar{
 		{
-			Name:  "REAPER_STORAGE_TYPE",
-			Value: "cassandra",
+			Name:  "REAPER_INCREMENTAL_REPAIR",
+			Value: "false",
 		},
 		{
 			Name:  "REAPER_ENABLE_DYNAMIC_SEED_LIST",
 			Value: "false",
 		},
+		{
+			Name:  "REAPER_BLACKLIST_TWCS",
+			Value: "false",
+		},
+		{
+			Name:  "REAPER_STORAGE_TYPE",
+			Value: "cassandra",
+		},
 		{
 			Name:  "REAPER_CASS_CONTACT_POINTS",
 			Value: "[" + reaper.Spec.ServerConfig.CassandraBackend.CassandraService + "]",
@@ -137,6 +336,253 @@ func TestNewDeployment(t *testing.T) {
 	assert.Equal(t, probe, container.ReadinessProbe)
 }
 
+func TestNewDeploymentWithPodTemplate(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	runAsNonRoot := true
+	reaper.Spec.PodTemplate = api.ReaperPodTemplate{
+		Labels:      map[string]string{"team": "db", mlabels.ReaperLabel: "override"},
+		Annotations: map[string]string{"example.com/scrape": "true"},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+		Tolerations:        []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		NodeSelector:       map[string]string{"disktype": "ssd"},
+		PriorityClassName:  "high-priority",
+		PodSecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: &runAsNonRoot},
+		SecurityContext:    &corev1.SecurityContext{RunAsNonRoot: &runAsNonRoot},
+		ImagePullSecrets:   []corev1.LocalObjectReference{{Name: "registry"}},
+		ServiceAccountName: "reaper",
+	}
+
+	deployment := newDeployment(reaper)
+	template := deployment.Spec.Template
+
+	assert.Equal(t, "db", template.Labels["team"])
+	assert.Equal(t, reaper.Name, template.Labels[mlabels.ReaperLabel], "operator labels should not be overridden")
+	assert.Equal(t, reaper.Spec.PodTemplate.Annotations, template.Annotations)
+
+	podSpec := template.Spec
+	assert.Equal(t, reaper.Spec.PodTemplate.Tolerations, podSpec.Tolerations)
+	assert.Equal(t, reaper.Spec.PodTemplate.NodeSelector, podSpec.NodeSelector)
+	assert.Equal(t, "high-priority", podSpec.PriorityClassName)
+	assert.Equal(t, reaper.Spec.PodTemplate.PodSecurityContext, podSpec.SecurityContext)
+	assert.Equal(t, reaper.Spec.PodTemplate.ImagePullSecrets, podSpec.ImagePullSecrets)
+	assert.Equal(t, "reaper", podSpec.ServiceAccountName)
+
+	container := podSpec.Containers[0]
+	assert.Equal(t, reaper.Spec.PodTemplate.Resources, container.Resources)
+	assert.Equal(t, reaper.Spec.PodTemplate.SecurityContext, container.SecurityContext)
+}
+
+func TestGetDatacenters(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.ServerConfig.CassandraBackend.Replication.NetworkTopologyStrategy = &map[string]int32{"DC2": 3, "DC1": 3}
+
+	assert.Equal(t, 0, len(getDatacenters(reaper)))
+
+	reaper.Spec.ServerConfig.DatacenterAvailability = api.DatacenterAvailabilityEach
+	assert.Equal(t, []string{"DC1", "DC2"}, getDatacenters(reaper))
+}
+
+func TestSetDeploymentDatacenter(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+
+	deployment := newDeployment(reaper)
+	setDeploymentDatacenter(deployment, reaper, "DC1")
+
+	assert.Equal(t, "test-reaper-dc1", deployment.Name)
+	assert.Equal(t, "DC1", deployment.Labels[mlabels.DatacenterLabel])
+	assert.Equal(t, "DC1", deployment.Spec.Template.Labels[mlabels.DatacenterLabel])
+	assert.Contains(t, deployment.Spec.Selector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      mlabels.DatacenterLabel,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{"DC1"},
+	})
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	assert.NoError(t, err)
+	assert.True(t, selector.Matches(labels.Set(deployment.Spec.Template.Labels)))
+
+	affinity := deployment.Spec.Template.Spec.Affinity
+	assert.NotNil(t, affinity)
+	term := affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm
+	assert.Equal(t, "DC1", term.LabelSelector.MatchLabels["cassandra.datastax.com/datacenter"])
+
+	// An affinity from the pod template is kept.
+	reaper.Spec.PodTemplate.Affinity = &corev1.Affinity{}
+	deployment = newDeployment(reaper)
+	setDeploymentDatacenter(deployment, reaper, "DC1")
+	assert.Equal(t, &corev1.Affinity{}, deployment.Spec.Template.Spec.Affinity)
+}
+
+func TestSetDeploymentDatacenterEnvVars(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+
+	// The configured service is used when the backend is not derived from a
+	// CassandraDatacenter.
+	deployment := newDeployment(reaper)
+	setDeploymentDatacenter(deployment, reaper, "DC1")
+
+	env := deployment.Spec.Template.Spec.Containers[0].Env
+	assert.Contains(t, env, corev1.EnvVar{Name: "REAPER_CASS_LOCAL_DC", Value: "DC1"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "REAPER_CASS_CONTACT_POINTS", Value: "[cassandra-svc]"})
+
+	reaper.Spec.ServerConfig.CassandraBackend.CassandraDatacenterRef = &api.CassandraDatacenterReference{Name: "DC1", Namespace: "cassandra"}
+	reaper.Spec.ServerConfig.CassandraBackend.Replication.NetworkTopologyStrategy = &map[string]int32{"DC1": 3, "DC2": 3}
+	reaper.Spec.ServerConfig.DatacenterAvailability = api.DatacenterAvailabilityEach
+
+	deployments, err := (&defaultReconciler{}).buildNewDeployments(ReaperRequest{Reaper: reaper})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(deployments))
+
+	for i, dc := range []string{"DC1", "DC2"} {
+		env := deployments[i].Spec.Template.Spec.Containers[0].Env
+		assert.Contains(t, env, corev1.EnvVar{Name: "REAPER_CASS_LOCAL_DC", Value: dc})
+		assert.Contains(t, env, corev1.EnvVar{Name: "REAPER_CASS_CONTACT_POINTS", Value: "[cassandra-" + dc + "-service.cassandra]"})
+	}
+}
+
+func TestIsDeploymentReady(t *testing.T) {
+	replicas := int32(3)
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.Replicas = &replicas
+
+	deployment := newDeployment(reaper)
+	assert.Equal(t, &replicas, deployment.Spec.Replicas)
+
+	deployment.Status.ReadyReplicas = 2
+	assert.False(t, isDeploymentReady(deployment))
+
+	deployment.Status.ReadyReplicas = 3
+	assert.True(t, isDeploymentReady(deployment))
+}
+
+func TestNewServerConfigEnvVars(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	config.NewValidator().SetDefaults(reaper)
+	reaper.Spec.ServerConfig.RepairIntensity = "0.5"
+	reaper.Spec.ServerConfig.BlacklistTwcsTables = true
+
+	envVars := newServerConfigEnvVars(reaper)
+
+	assert.ElementsMatch(t, envVars, []corev1.EnvVar{
+		{Name: "REAPER_DATACENTER_AVAILABILITY", Value: "ALL"},
+		{Name: "REAPER_HANGING_REPAIR_TIMEOUT_MINS", Value: "30"},
+		{Name: "REAPER_INCREMENTAL_REPAIR", Value: "false"},
+		{Name: "REAPER_REPAIR_INTENSITY", Value: "0.5"},
+		{Name: "REAPER_REPAIR_PARALELLISM", Value: "DATACENTER_AWARE"},
+		{Name: "REAPER_REPAIR_RUN_THREADS", Value: "15"},
+		{Name: "REAPER_SCHEDULE_DAYS_BETWEEN", Value: "7"},
+		{Name: "REAPER_ENABLE_CROSS_ORIGIN", Value: "true"},
+		{Name: "REAPER_ENABLE_DYNAMIC_SEED_LIST", Value: "false"},
+		{Name: "REAPER_BLACKLIST_TWCS", Value: "true"},
+		{Name: "REAPER_JMX_CONNECTION_TIMEOUT_IN_SECONDS", Value: "20"},
+		{Name: "REAPER_SEGMENT_COUNT_PER_NODE", Value: "16"},
+	})
+}
+
+func TestNewServerConfigEnvVarsAfterScaleUp(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	replicas := int32(1)
+	reaper.Spec.Replicas = &replicas
+	config.NewValidator().SetDefaults(reaper)
+
+	assert.Contains(t, newServerConfigEnvVars(reaper), corev1.EnvVar{Name: "REAPER_ENABLE_DYNAMIC_SEED_LIST", Value: "false"})
+
+	replicas = 3
+	config.NewValidator().SetDefaults(reaper)
+
+	assert.Contains(t, newServerConfigEnvVars(reaper), corev1.EnvVar{Name: "REAPER_ENABLE_DYNAMIC_SEED_LIST", Value: "true"})
+
+	// An explicit setting is not overridden.
+	enabled := false
+	reaper.Spec.ServerConfig.EnableDynamicSeedList = &enabled
+
+	assert.Contains(t, newServerConfigEnvVars(reaper), corev1.EnvVar{Name: "REAPER_ENABLE_DYNAMIC_SEED_LIST", Value: "false"})
+}
+
+func TestNewServerConfigEnvVarsWithAutoScheduling(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.ServerConfig.AutoScheduling = api.AutoScheduling{
+		Enabled:           true,
+		ExcludedKeyspaces: []string{"ks1", "ks2"},
+	}
+	config.NewValidator().SetDefaults(reaper)
+
+	envVars := newServerConfigEnvVars(reaper)
+
+	assert.Subset(t, envVars, []corev1.EnvVar{
+		{Name: "REAPER_AUTO_SCHEDULING_ENABLED", Value: "true"},
+		{Name: "REAPER_AUTO_SCHEDULING_INITIAL_DELAY_PERIOD", Value: api.DefaultAutoSchedulingInitialDelayPeriod},
+		{Name: "REAPER_AUTO_SCHEDULING_PERIOD_BETWEEN_POLLS", Value: api.DefaultAutoSchedulingPeriodBetweenPolls},
+		{Name: "REAPER_AUTO_SCHEDULING_TIME_BEFORE_FIRST_SCHEDULE", Value: api.DefaultAutoSchedulingTimeBeforeFirstSchedule},
+		{Name: "REAPER_AUTO_SCHEDULING_SCHEDULE_SPREAD_PERIOD", Value: api.DefaultAutoSchedulingScheduleSpreadPeriod},
+		{Name: "REAPER_AUTO_SCHEDULING_EXCLUDED_KEYSPACES", Value: "[ks1, ks2]"},
+	})
+}
+
+func TestNewDeploymentWithUIAuth(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.UIAuth = &api.ReaperUIAuth{SecretRef: corev1.LocalObjectReference{Name: "reaper-ui"}}
+
+	deployment := newDeployment(reaper)
+
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "REAPER_AUTH_ENABLED", Value: "true"})
+}
+
+func TestAddDeploymentTLS(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	tls := &api.TLSConfig{CQLEnabled: true, JMXEnabled: true, SecretRef: corev1.LocalObjectReference{Name: "reaper-tls"}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: reaper.Namespace, Name: "reaper-tls"},
+		Data: map[string][]byte{
+			TruststoreKey:         []byte("truststore"),
+			TruststorePasswordKey: []byte("changeit"),
+		},
+	}
+	deployment := newDeployment(reaper)
+
+	addDeploymentTLS(deployment, tls, secret)
+
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, 1, len(podSpec.Volumes))
+	assert.Equal(t, "reaper-tls", podSpec.Volumes[0].Secret.SecretName)
+
+	container := podSpec.Containers[0]
+	assert.Equal(t, []corev1.VolumeMount{newTLSVolumeMount()}, container.VolumeMounts)
+	assert.Contains(t, container.Env, newSecretKeyEnvVar("TLS_TRUSTSTORE_PASSWORD", "reaper-tls", TruststorePasswordKey))
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "REAPER_CASS_NATIVE_PROTOCOL_SSL_ENCRYPTION_ENABLED", Value: "true"})
+	assert.Contains(t, container.Env, corev1.EnvVar{
+		Name:  "JAVA_OPTS",
+		Value: "-Djavax.net.ssl.trustStore=/etc/reaper/tls/truststore.jks -Djavax.net.ssl.trustStorePassword=$(TLS_TRUSTSTORE_PASSWORD) -Dssl.enable=true",
+	})
+}
+
+func TestAddSchemaJobTLS(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: reaper.Namespace, Name: "reaper-tls"},
+		Data: map[string][]byte{
+			CACertKey:  []byte("ca"),
+			TLSCertKey: []byte("cert"),
+			TLSKeyKey:  []byte("key"),
+		},
+	}
+	job := newSchemaJob(reaper)
+
+	addSchemaJobTLS(job, secret)
+
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, 1, len(podSpec.Volumes))
+	assert.Equal(t, []corev1.VolumeMount{newTLSVolumeMount()}, podSpec.Containers[0].VolumeMounts)
+
+	env := podSpec.Containers[0].Env
+	assert.Contains(t, env, corev1.EnvVar{Name: "TLS_ENABLED", Value: "true"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "TLS_CA_CERT", Value: "/etc/reaper/tls/ca.crt"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "TLS_CERT", Value: "/etc/reaper/tls/tls.crt"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "TLS_KEY", Value: "/etc/reaper/tls/tls.key"})
+}
+
 func newReaperWithCassandraBackend() *api.Reaper {
 	namespace := "service-test"
 	reaperName := "test-reaper"
@@ -3,15 +3,70 @@ package reconcile
 import (
 	"fmt"
 
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 )
 
 const (
 	JmxAuthSecretName = "reaper-jmx"
+
+	// The keys of the TLS secret. The JKS stores are used by Reaper and the PEM files by the
+	// schema job.
+	TruststoreKey         = "truststore.jks"
+	TruststorePasswordKey = "truststore-password"
+	KeystoreKey           = "keystore.jks"
+	KeystorePasswordKey   = "keystore-password"
+	CACertKey             = "ca.crt"
+	TLSCertKey            = "tls.crt"
+	TLSKeyKey             = "tls.key"
 )
 
+// Returns the names of the secrets that the Reaper references, without duplicates.
+func GetSecretNames(reaper *api.Reaper) []string {
+	cfg := reaper.Spec.ServerConfig
+	refs := []string{cfg.JmxUserSecretName}
+	if cfg.CassandraBackend != nil {
+		refs = append(refs, cfg.CassandraBackend.AuthProvider.SecretRef.Name)
+	}
+	if cfg.TLS != nil {
+		refs = append(refs, cfg.TLS.SecretRef.Name)
+	}
+	if reaper.Spec.UIAuth != nil {
+		refs = append(refs, reaper.Spec.UIAuth.SecretRef.Name)
+	}
+
+	names := make([]string, 0, len(refs))
+	for _, name := range refs {
+		if len(name) > 0 && !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func contains(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 type SecretsManager interface {
 	GetJmxAuthCredentials(secret *corev1.Secret) (*corev1.EnvVar, *corev1.EnvVar, error)
+
+	// Returns the env vars through which Reaper reads the credentials for the Cassandra
+	// backend. An error is returned if the secret does not have the username and password keys.
+	GetCassandraAuthCredentials(secret *corev1.Secret) (*corev1.EnvVar, *corev1.EnvVar, error)
+
+	// Returns the env vars through which Reaper reads the credentials for its web UI and REST
+	// API. An error is returned if the secret does not have the username and password keys.
+	GetUIAuthCredentials(secret *corev1.Secret) (*corev1.EnvVar, *corev1.EnvVar, error)
+
+	// Checks that the secret has the keys required by the TLS configuration. The PEM encoded
+	// CA certificate is only required when requireCACert is true.
+	ValidateTLSSecret(secret *corev1.Secret, requireCACert bool) error
 }
 
 type defaultSecretsManager struct {
@@ -22,37 +77,49 @@ func NewSecretsManager() SecretsManager {
 }
 
 func (s *defaultSecretsManager) GetJmxAuthCredentials(secret *corev1.Secret) (*corev1.EnvVar, *corev1.EnvVar, error) {
+	return getCredentials(secret, "jmx auth", "REAPER_JMX_AUTH_USERNAME", "REAPER_JMX_AUTH_PASSWORD")
+}
+
+func (s *defaultSecretsManager) GetCassandraAuthCredentials(secret *corev1.Secret) (*corev1.EnvVar, *corev1.EnvVar, error) {
+	return getCredentials(secret, "cassandra auth", "REAPER_CASS_AUTH_USERNAME", "REAPER_CASS_AUTH_PASSWORD")
+}
+
+func (s *defaultSecretsManager) GetUIAuthCredentials(secret *corev1.Secret) (*corev1.EnvVar, *corev1.EnvVar, error) {
+	return getCredentials(secret, "ui auth", "REAPER_AUTH_USER", "REAPER_AUTH_PASSWORD")
+}
+
+func (s *defaultSecretsManager) ValidateTLSSecret(secret *corev1.Secret, requireCACert bool) error {
+	required := []string{TruststoreKey, TruststorePasswordKey}
+	if _, ok := secret.Data[KeystoreKey]; ok {
+		required = append(required, KeystorePasswordKey)
+	}
+	if _, ok := secret.Data[TLSCertKey]; ok {
+		required = append(required, TLSKeyKey)
+	}
+	if requireCACert {
+		required = append(required, CACertKey)
+	}
+
+	for _, key := range required {
+		if _, ok := secret.Data[key]; !ok {
+			return fmt.Errorf("%s key not found in tls secret %s", key, secret.Name)
+		}
+	}
+
+	return nil
+}
+
+func getCredentials(secret *corev1.Secret, secretType, usernameEnvVarName, passwordEnvVarName string) (*corev1.EnvVar, *corev1.EnvVar, error) {
 	if _, ok := secret.Data["username"]; !ok {
-		return nil, nil, fmt.Errorf("username key not found in jmx auth secret %s", secret.Name)
+		return nil, nil, fmt.Errorf("username key not found in %s secret %s", secretType, secret.Name)
 	}
 
 	if _, ok := secret.Data["password"]; !ok {
-		return nil, nil, fmt.Errorf("password key not found in jmx auth secret %s", secret.Name)
-	}
-
-	usernameEnvVar := corev1.EnvVar{
-		Name: "REAPER_JMX_AUTH_USERNAME",
-		ValueFrom: &corev1.EnvVarSource{
-			SecretKeyRef: &corev1.SecretKeySelector{
-				LocalObjectReference: corev1.LocalObjectReference{
-					Name: secret.Name,
-				},
-				Key: "username",
-			},
-		},
-	}
-
-	passwordEnvVar := corev1.EnvVar{
-		Name: "REAPER_JMX_AUTH_PASSWORD",
-		ValueFrom: &corev1.EnvVarSource{
-			SecretKeyRef: &corev1.SecretKeySelector{
-				LocalObjectReference: corev1.LocalObjectReference{
-					Name: secret.Name,
-				},
-				Key: "password",
-			},
-		},
+		return nil, nil, fmt.Errorf("password key not found in %s secret %s", secretType, secret.Name)
 	}
 
+	usernameEnvVar := newSecretKeyEnvVar(usernameEnvVarName, secret.Name, "username")
+	passwordEnvVar := newSecretKeyEnvVar(passwordEnvVarName, secret.Name, "password")
+
 	return &usernameEnvVar, &passwordEnvVar, nil
 }
@@ -0,0 +1,74 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetCassandraAuthCredentials(t *testing.T) {
+	secretsManager := NewSecretsManager()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "reaper-cql"},
+		Data: map[string][]byte{
+			"username": []byte("reaper"),
+			"password": []byte("secret"),
+		},
+	}
+
+	usernameEnvVar, passwordEnvVar, err := secretsManager.GetCassandraAuthCredentials(secret)
+	assert.NoError(t, err)
+	assert.Equal(t, "REAPER_CASS_AUTH_USERNAME", usernameEnvVar.Name)
+	assert.Equal(t, "reaper-cql", usernameEnvVar.ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "username", usernameEnvVar.ValueFrom.SecretKeyRef.Key)
+	assert.Equal(t, "REAPER_CASS_AUTH_PASSWORD", passwordEnvVar.Name)
+	assert.Equal(t, "reaper-cql", passwordEnvVar.ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "password", passwordEnvVar.ValueFrom.SecretKeyRef.Key)
+
+	delete(secret.Data, "password")
+	_, _, err = secretsManager.GetCassandraAuthCredentials(secret)
+	assert.Error(t, err)
+}
+
+func TestValidateTLSSecret(t *testing.T) {
+	secretsManager := NewSecretsManager()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "reaper-tls"},
+		Data: map[string][]byte{
+			TruststoreKey:         []byte("truststore"),
+			TruststorePasswordKey: []byte("changeit"),
+		},
+	}
+
+	assert.NoError(t, secretsManager.ValidateTLSSecret(secret, false))
+	assert.Error(t, secretsManager.ValidateTLSSecret(secret, true))
+
+	secret.Data[KeystoreKey] = []byte("keystore")
+	assert.Error(t, secretsManager.ValidateTLSSecret(secret, false))
+
+	secret.Data[KeystorePasswordKey] = []byte("changeit")
+	secret.Data[CACertKey] = []byte("ca")
+	assert.NoError(t, secretsManager.ValidateTLSSecret(secret, true))
+
+	delete(secret.Data, TruststoreKey)
+	assert.Error(t, secretsManager.ValidateTLSSecret(secret, false))
+}
+
+func TestGetSecretNames(t *testing.T) {
+	reaper := &api.Reaper{}
+	assert.Equal(t, 0, len(GetSecretNames(reaper)))
+
+	reaper.Spec.ServerConfig = api.ServerConfig{
+		JmxUserSecretName: "reaper-jmx",
+		CassandraBackend: &api.CassandraBackend{
+			AuthProvider: api.AuthProvider{SecretRef: corev1.LocalObjectReference{Name: "reaper-cql"}},
+		},
+		TLS: &api.TLSConfig{SecretRef: corev1.LocalObjectReference{Name: "reaper-tls"}},
+	}
+	reaper.Spec.UIAuth = &api.ReaperUIAuth{SecretRef: corev1.LocalObjectReference{Name: "reaper-cql"}}
+
+	assert.Equal(t, []string{"reaper-jmx", "reaper-cql", "reaper-tls"}, GetSecretNames(reaper))
+}
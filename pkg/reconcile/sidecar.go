@@ -0,0 +1,226 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	mlabels "github.com/thelastpickle/reaper-operator/pkg/labels"
+	"github.com/thelastpickle/reaper-operator/pkg/status"
+	"github.com/thelastpickle/reaper-operator/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	SidecarContainerName = "reaper"
+
+	// Reaper's default ports cannot be used in the Cassandra pods since the management API of
+	// cass-operator's Cassandra container listens on 8080.
+	sidecarAppPort   = 8090
+	sidecarAdminPort = 8091
+
+	// The hash of the sidecar that is injected into the pod template of a CassandraDatacenter.
+	sidecarHashAnnotation = "reaper.cassandra-reaper.io/sidecar-hash"
+
+	// Pods are not watched, so the sidecars are checked periodically once they are ready.
+	sidecarStatusCheckDelay = 1 * time.Minute
+)
+
+// Injects the Reaper container into the pod template of the CassandraDatacenter. Returns true
+// if the pod template changed in which case the CassandraDatacenter has to be updated. The
+// sidecar is built from the same settings as the Reaper deployment, but only the resources and
+// security context of the Reaper's pod template apply to it.
+func (r *defaultReconciler) ReconcileSidecar(ctx context.Context, req ReaperRequest, cassdc *cassdcv1beta1.CassandraDatacenter) (bool, error) {
+	deployment, err := r.buildNewDeployment(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to build reaper sidecar: %w", err)
+	}
+
+	return injectSidecar(cassdc, newSidecar(deployment)), nil
+}
+
+// Returns a pod template with the Reaper container, its volumes and the label that the Reaper
+// service selects.
+func newSidecar(deployment *appsv1.Deployment) *corev1.PodTemplateSpec {
+	podSpec := deployment.Spec.Template.Spec
+	container := podSpec.Containers[0].DeepCopy()
+
+	container.Name = SidecarContainerName
+	container.Ports = []corev1.ContainerPort{
+		{
+			Name:          "app",
+			ContainerPort: sidecarAppPort,
+			Protocol:      "TCP",
+		},
+		{
+			Name:          "admin",
+			ContainerPort: sidecarAdminPort,
+			Protocol:      "TCP",
+		},
+	}
+	container.Env = append(container.Env, []corev1.EnvVar{
+		{
+			Name:  "REAPER_SERVER_APP_PORT",
+			Value: strconv.Itoa(sidecarAppPort),
+		},
+		{
+			Name:  "REAPER_SERVER_ADMIN_PORT",
+			Value: strconv.Itoa(sidecarAdminPort),
+		},
+	}...)
+
+	healthProbe := container.LivenessProbe.DeepCopy()
+	healthProbe.HTTPGet.Port = intstr.FromInt(sidecarAdminPort)
+	container.LivenessProbe = healthProbe
+	container.ReadinessProbe = healthProbe
+
+	// The secrets hash restarts the Cassandra pods when a secret of the sidecar changes.
+	var annotations map[string]string
+	if hash, found := deployment.Spec.Template.Annotations[secretsHashAnnotation]; found {
+		annotations = map[string]string{secretsHashAnnotation: hash}
+	}
+
+	return &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{mlabels.ReaperLabel: deployment.Labels[mlabels.ReaperLabel]},
+			Annotations: annotations,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{*container},
+			Volumes:    podSpec.Volumes,
+		},
+	}
+}
+
+// Replaces the sidecar in the pod template of the CassandraDatacenter unless it is already up
+// to date. Returns true if the pod template changed.
+func injectSidecar(cassdc *cassdcv1beta1.CassandraDatacenter, sidecar *corev1.PodTemplateSpec) bool {
+	hash := util.DeepHashString(sidecar)
+
+	template := cassdc.Spec.PodTemplateSpec
+	if template != nil && template.Annotations[sidecarHashAnnotation] == hash {
+		return false
+	}
+
+	RemoveSidecar(cassdc)
+	if template == nil {
+		template = &corev1.PodTemplateSpec{}
+	}
+
+	template.Labels = util.MergeMap(map[string]string{}, template.Labels, sidecar.Labels)
+	template.Annotations = util.MergeMap(map[string]string{}, template.Annotations, sidecar.Annotations, map[string]string{sidecarHashAnnotation: hash})
+	template.Spec.Containers = append(template.Spec.Containers, sidecar.Spec.Containers...)
+	template.Spec.Volumes = append(template.Spec.Volumes, sidecar.Spec.Volumes...)
+	cassdc.Spec.PodTemplateSpec = template
+
+	return true
+}
+
+// Removes the Reaper sidecar from the pod template of the CassandraDatacenter. Returns true if
+// the pod template had the sidecar.
+func RemoveSidecar(cassdc *cassdcv1beta1.CassandraDatacenter) bool {
+	template := cassdc.Spec.PodTemplateSpec
+	if template == nil {
+		return false
+	}
+	if _, found := template.Annotations[sidecarHashAnnotation]; !found {
+		return false
+	}
+
+	delete(template.Annotations, sidecarHashAnnotation)
+	delete(template.Annotations, secretsHashAnnotation)
+	delete(template.Labels, mlabels.ReaperLabel)
+
+	containers := make([]corev1.Container, 0, len(template.Spec.Containers))
+	for _, container := range template.Spec.Containers {
+		if container.Name != SidecarContainerName {
+			containers = append(containers, container)
+		}
+	}
+	template.Spec.Containers = containers
+
+	volumes := make([]corev1.Volume, 0, len(template.Spec.Volumes))
+	for _, volume := range template.Spec.Volumes {
+		// The TLS volume is the only volume that the sidecar adds.
+		if volume.Name != tlsVolumeName {
+			volumes = append(volumes, volume)
+		}
+	}
+	template.Spec.Volumes = volumes
+
+	return true
+}
+
+// Reports the readiness of the sidecars of the Cassandra pods in the Reaper's status. The Reaper
+// is ready when every sidecar is ready.
+func (r *defaultReconciler) reconcileSidecarStatus(ctx context.Context, req ReaperRequest) (*ctrl.Result, error) {
+	reaper := req.Reaper
+
+	pods := &corev1.PodList{}
+	err := r.List(ctx, pods, client.InNamespace(reaper.Namespace), client.MatchingLabels{mlabels.ReaperLabel: reaper.Name},
+		client.HasLabels{cassdcv1beta1.DatacenterLabel})
+	if err != nil {
+		req.Logger.Error(err, "failed to list cassandra pods")
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+
+	sidecars := newSidecarStatuses(pods.Items)
+
+	ready := 0
+	for _, sidecar := range sidecars {
+		if sidecar.Ready {
+			ready++
+		}
+	}
+
+	condStatus, reason, message := corev1.ConditionTrue, status.SidecarsReadyReason, fmt.Sprintf("%d of %d sidecars are ready", ready, len(sidecars))
+	if len(sidecars) == 0 {
+		condStatus, reason, message = corev1.ConditionFalse, status.SidecarsNotReadyReason, "no cassandra pods run the reaper sidecar"
+	} else if ready < len(sidecars) {
+		condStatus, reason = corev1.ConditionFalse, status.SidecarsNotReadyReason
+	}
+
+	if err := req.StatusManager.SetSidecarStatus(ctx, reaper, sidecars, condStatus, reason, message); err != nil {
+		req.Logger.Error(err, "failed to update status")
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, err
+	}
+
+	if condStatus != corev1.ConditionTrue {
+		req.Logger.Info("sidecars not ready", "message", message)
+		return &ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	return &ctrl.Result{RequeueAfter: sidecarStatusCheckDelay}, nil
+}
+
+// Returns the status of the sidecars of the pods sorted by pod name. Nil is returned if there
+// are no pods.
+func newSidecarStatuses(pods []corev1.Pod) []api.ReaperSidecarStatus {
+	var sidecars []api.ReaperSidecarStatus
+	for _, pod := range pods {
+		sidecar := api.ReaperSidecarStatus{
+			Pod:        pod.Name,
+			Datacenter: pod.Labels[cassdcv1beta1.DatacenterLabel],
+		}
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == SidecarContainerName {
+				sidecar.Ready = containerStatus.Ready
+			}
+		}
+		sidecars = append(sidecars, sidecar)
+	}
+
+	sort.Slice(sidecars, func(i, j int) bool {
+		return sidecars[i].Pod < sidecars[j].Pod
+	})
+
+	return sidecars
+}
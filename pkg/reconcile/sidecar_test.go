@@ -0,0 +1,105 @@
+package reconcile
+
+import (
+	"testing"
+
+	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	"github.com/stretchr/testify/assert"
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	mlabels "github.com/thelastpickle/reaper-operator/pkg/labels"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewSidecar(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.ServerConfig.DatacenterAvailability = api.DatacenterAvailabilitySidecar
+
+	sidecar := newSidecar(newDeployment(reaper))
+
+	assert.Equal(t, map[string]string{mlabels.ReaperLabel: reaper.Name}, sidecar.Labels)
+	assert.Equal(t, 1, len(sidecar.Spec.Containers))
+
+	container := sidecar.Spec.Containers[0]
+	assert.Equal(t, SidecarContainerName, container.Name)
+	assert.Equal(t, int32(8090), container.Ports[0].ContainerPort)
+	assert.Equal(t, 8091, container.ReadinessProbe.HTTPGet.Port.IntValue())
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "REAPER_DATACENTER_AVAILABILITY", Value: "SIDECAR"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "REAPER_SERVER_APP_PORT", Value: "8090"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "REAPER_SERVER_ADMIN_PORT", Value: "8091"})
+}
+
+func TestInjectSidecar(t *testing.T) {
+	reaper := newReaperWithCassandraBackend()
+	reaper.Spec.ServerConfig.DatacenterAvailability = api.DatacenterAvailabilitySidecar
+
+	deployment := newDeployment(reaper)
+	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{newTLSVolume("reaper-tls")}
+	sidecar := newSidecar(deployment)
+
+	cassdc := &cassdcv1beta1.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Namespace: reaper.Namespace, Name: "dc1"},
+		Spec: cassdcv1beta1.CassandraDatacenterSpec{
+			PodTemplateSpec: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "db"}},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "cassandra"}},
+					Volumes:    []corev1.Volume{{Name: "config"}},
+				},
+			},
+		},
+	}
+
+	assert.True(t, injectSidecar(cassdc, sidecar))
+
+	template := cassdc.Spec.PodTemplateSpec
+	assert.Equal(t, "db", template.Labels["team"])
+	assert.Equal(t, reaper.Name, template.Labels[mlabels.ReaperLabel])
+	assert.Equal(t, 2, len(template.Spec.Containers))
+	assert.Equal(t, SidecarContainerName, template.Spec.Containers[1].Name)
+	assert.Equal(t, 2, len(template.Spec.Volumes))
+
+	assert.False(t, injectSidecar(cassdc, sidecar), "an up to date sidecar should not be injected again")
+
+	// A changed sidecar replaces the current one.
+	reaper.Spec.Image = "thelastpickle/cassandra-reaper:2.1.0"
+	deployment = newDeployment(reaper)
+	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{newTLSVolume("reaper-tls")}
+	assert.True(t, injectSidecar(cassdc, newSidecar(deployment)))
+	assert.Equal(t, 2, len(template.Spec.Containers))
+	assert.Equal(t, reaper.Spec.Image, template.Spec.Containers[1].Image)
+	assert.Equal(t, 2, len(template.Spec.Volumes))
+
+	assert.True(t, RemoveSidecar(cassdc))
+	assert.Equal(t, []corev1.Container{{Name: "cassandra"}}, template.Spec.Containers)
+	assert.Equal(t, []corev1.Volume{{Name: "config"}}, template.Spec.Volumes)
+	assert.Equal(t, map[string]string{"team": "db"}, template.Labels)
+
+	assert.False(t, RemoveSidecar(cassdc))
+}
+
+func TestNewSidecarStatuses(t *testing.T) {
+	newPod := func(name string, ready bool) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{cassdcv1beta1.DatacenterLabel: "dc1"},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "cassandra", Ready: true},
+					{Name: SidecarContainerName, Ready: ready},
+				},
+			},
+		}
+	}
+
+	sidecars := newSidecarStatuses([]corev1.Pod{newPod("test-dc1-rack1-sts-1", false), newPod("test-dc1-rack1-sts-0", true)})
+
+	assert.Equal(t, []api.ReaperSidecarStatus{
+		{Pod: "test-dc1-rack1-sts-0", Datacenter: "dc1", Ready: true},
+		{Pod: "test-dc1-rack1-sts-1", Datacenter: "dc1", Ready: false},
+	}, sidecars)
+
+	assert.Equal(t, 0, len(newSidecarStatuses(nil)))
+}
@@ -0,0 +1,197 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ValidationSucceededReason = "ValidationSucceeded"
+	ValidationFailedReason    = "ValidationFailed"
+
+	DatacenterUnavailableReason = "CassandraDatacenterUnavailable"
+
+	ServiceCreatedReason      = "ServiceCreated"
+	ServiceCreateFailedReason = "ServiceCreateFailed"
+
+	SchemaNotRequiredReason     = "SchemaNotRequired"
+	SchemaJobRunningReason      = "SchemaJobRunning"
+	SchemaJobFailedReason       = "SchemaJobFailed"
+	SchemaJobCompletedReason    = "SchemaJobCompleted"
+	SchemaJobCreateFailedReason = "SchemaJobCreateFailed"
+	ReplicationUpdatingReason   = "ReplicationUpdating"
+	ReplicationUpdatedReason    = "ReplicationUpdated"
+
+	DeploymentReadyReason       = "DeploymentReady"
+	DeploymentNotReadyReason    = "DeploymentNotReady"
+	DeploymentBuildFailedReason = "DeploymentBuildFailed"
+
+	SidecarsReadyReason    = "SidecarsReady"
+	SidecarsNotReadyReason = "SidecarsNotReady"
+
+	ClustersRegisteredReason   = "ClustersRegistered"
+	NoClustersRegisteredReason = "NoClustersRegistered"
+)
+
+// Returns the condition of the given type or nil if the status does not have it.
+func GetCondition(status *api.ReaperStatus, condType api.ReaperConditionType) *api.ReaperCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == condType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// Returns true if the status has the condition and the condition is true.
+func IsConditionTrue(status *api.ReaperStatus, condType api.ReaperConditionType) bool {
+	cond := GetCondition(status, condType)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// Adds or replaces the condition of the same type. The last transition time of an existing
+// condition is kept unless its status changes. Returns true if the status was modified.
+func SetCondition(status *api.ReaperStatus, cond api.ReaperCondition) bool {
+	current := GetCondition(status, cond.Type)
+	if current == nil {
+		if cond.LastTransitionTime.IsZero() {
+			cond.LastTransitionTime = metav1.Now()
+		}
+		status.Conditions = append(status.Conditions, cond)
+		return true
+	}
+
+	if current.Status == cond.Status && current.Reason == cond.Reason && current.Message == cond.Message &&
+		current.ObservedGeneration == cond.ObservedGeneration {
+		return false
+	}
+
+	if current.Status == cond.Status {
+		cond.LastTransitionTime = current.LastTransitionTime
+	} else if cond.LastTransitionTime.IsZero() {
+		cond.LastTransitionTime = metav1.Now()
+	}
+	*current = cond
+
+	return true
+}
+
+// Sets the condition on the Reaper and patch the status. .status.observedGeneration is
+// updated as well. Nothing is patched if neither changed.
+func (s *StatusManager) SetCondition(ctx context.Context, reaper *api.Reaper, condType api.ReaperConditionType, status corev1.ConditionStatus, reason, message string) error {
+	patch := client.MergeFrom(reaper.DeepCopy())
+
+	cond := api.ReaperCondition{
+		Type:               condType,
+		Status:             status,
+		ObservedGeneration: reaper.Generation,
+		Reason:             reason,
+		Message:            message,
+	}
+	if !SetCondition(&reaper.Status, cond) && reaper.Status.ObservedGeneration == reaper.Generation {
+		return nil
+	}
+	reaper.Status.ObservedGeneration = reaper.Generation
+
+	return s.Status().Patch(ctx, reaper, patch)
+}
+
+// Sets .status.schemaJobFailures along with the SchemaInitialized condition and patch the
+// status. Nothing is patched if neither changed.
+func (s *StatusManager) SetSchemaJobStatus(ctx context.Context, reaper *api.Reaper, failures int32, status corev1.ConditionStatus, reason, message string) error {
+	patch := client.MergeFrom(reaper.DeepCopy())
+
+	cond := api.ReaperCondition{
+		Type:               api.SchemaInitialized,
+		Status:             status,
+		ObservedGeneration: reaper.Generation,
+		Reason:             reason,
+		Message:            message,
+	}
+	if !SetCondition(&reaper.Status, cond) && reaper.Status.SchemaJobFailures == failures {
+		return nil
+	}
+	reaper.Status.SchemaJobFailures = failures
+
+	return s.Status().Patch(ctx, reaper, patch)
+}
+
+// Sets .status.replication to the replication applied by a schema job and marks the schema as
+// initialized. .status.schemaJobFailures is reset. Nothing is patched if the status is unchanged.
+func (s *StatusManager) SetSchemaApplied(ctx context.Context, reaper *api.Reaper, replication api.ReplicationConfig, reason, message string) error {
+	patch := client.MergeFrom(reaper.DeepCopy())
+
+	cond := api.ReaperCondition{
+		Type:               api.SchemaInitialized,
+		Status:             corev1.ConditionTrue,
+		ObservedGeneration: reaper.Generation,
+		Reason:             reason,
+		Message:            message,
+	}
+	updated := SetCondition(&reaper.Status, cond)
+	if reaper.Status.Replication == nil || !reflect.DeepEqual(*reaper.Status.Replication, replication) {
+		reaper.Status.Replication = replication.DeepCopy()
+		updated = true
+	}
+	if reaper.Status.SchemaJobFailures != 0 {
+		reaper.Status.SchemaJobFailures = 0
+		updated = true
+	}
+
+	if !updated {
+		return nil
+	}
+	return s.Status().Patch(ctx, reaper, patch)
+}
+
+// Sets .status.sidecars along with the SidecarsReady condition and patch the status.
+// .status.ready follows the condition. Nothing is patched if the status is unchanged.
+func (s *StatusManager) SetSidecarStatus(ctx context.Context, reaper *api.Reaper, sidecars []api.ReaperSidecarStatus, status corev1.ConditionStatus, reason, message string) error {
+	patch := client.MergeFrom(reaper.DeepCopy())
+
+	cond := api.ReaperCondition{
+		Type:               api.SidecarsReady,
+		Status:             status,
+		ObservedGeneration: reaper.Generation,
+		Reason:             reason,
+		Message:            message,
+	}
+	updated := SetCondition(&reaper.Status, cond)
+	if !reflect.DeepEqual(reaper.Status.Sidecars, sidecars) {
+		reaper.Status.Sidecars = sidecars
+		updated = true
+	}
+	if ready := status == corev1.ConditionTrue; reaper.Status.Ready != ready {
+		reaper.Status.Ready = ready
+		updated = true
+	}
+
+	if !updated {
+		return nil
+	}
+	return s.Status().Patch(ctx, reaper, patch)
+}
+
+func newClustersRegisteredCondition(reaper *api.Reaper) api.ReaperCondition {
+	cond := api.ReaperCondition{
+		Type:               api.ClustersRegistered,
+		ObservedGeneration: reaper.Generation,
+	}
+	if len(reaper.Status.Clusters) == 0 {
+		cond.Status = corev1.ConditionFalse
+		cond.Reason = NoClustersRegisteredReason
+		cond.Message = "no clusters are registered with Reaper"
+	} else {
+		cond.Status = corev1.ConditionTrue
+		cond.Reason = ClustersRegisteredReason
+		cond.Message = fmt.Sprintf("registered clusters: %s", strings.Join(reaper.Status.Clusters, ", "))
+	}
+	return cond
+}
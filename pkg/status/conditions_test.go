@@ -0,0 +1,51 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetCondition(t *testing.T) {
+	status := &api.ReaperStatus{}
+
+	if !SetCondition(status, api.ReaperCondition{Type: api.ServiceReady, Status: corev1.ConditionFalse, Reason: ServiceCreateFailedReason}) {
+		t.Fatalf("expected new condition to be added")
+	}
+	cond := GetCondition(status, api.ServiceReady)
+	if cond == nil || cond.LastTransitionTime.IsZero() {
+		t.Fatalf("expected condition with a last transition time, got %+v", cond)
+	}
+
+	if SetCondition(status, api.ReaperCondition{Type: api.ServiceReady, Status: corev1.ConditionFalse, Reason: ServiceCreateFailedReason}) {
+		t.Errorf("expected no change when setting an identical condition")
+	}
+
+	// The transition time should only change along with the status.
+	lastTransition := metav1.NewTime(time.Now().Add(-time.Hour))
+	cond.LastTransitionTime = lastTransition
+	if !SetCondition(status, api.ReaperCondition{Type: api.ServiceReady, Status: corev1.ConditionFalse, Reason: ServiceCreateFailedReason, Message: "retrying"}) {
+		t.Errorf("expected condition to be updated")
+	}
+	if cond = GetCondition(status, api.ServiceReady); !cond.LastTransitionTime.Equal(&lastTransition) {
+		t.Errorf("expected last transition time to be unchanged, got %s", cond.LastTransitionTime)
+	}
+
+	SetCondition(status, api.ReaperCondition{Type: api.ServiceReady, Status: corev1.ConditionTrue, Reason: ServiceCreatedReason})
+	if cond = GetCondition(status, api.ServiceReady); cond.LastTransitionTime.Equal(&lastTransition) {
+		t.Errorf("expected last transition time to be updated")
+	}
+
+	if len(status.Conditions) != 1 {
+		t.Errorf("expected 1 condition, got %d", len(status.Conditions))
+	}
+	if !IsConditionTrue(status, api.ServiceReady) {
+		t.Errorf("expected condition %s to be true", api.ServiceReady)
+	}
+	if IsConditionTrue(status, api.DeploymentAvailable) {
+		t.Errorf("expected condition %s to not be true", api.DeploymentAvailable)
+	}
+}
@@ -2,6 +2,7 @@ package status
 
 import (
 	"context"
+	"reflect"
 
 	cassdcv1beta1 "github.com/datastax/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
@@ -28,22 +29,28 @@ func (s *StatusManager) updateReady(ctx context.Context, reaper *api.Reaper, rea
 	return s.Status().Patch(ctx, reaper, patch)
 }
 
-// Adds the cluster to .status.clusters if it not already in the list. The status is patch
-// updated if the list is modified.
+// Adds the cluster to .status.clusters if it not already in the list and updates the
+// ClustersRegistered condition. The status is patch updated if it is modified.
 func (s *StatusManager) AddClusterToStatus(ctx context.Context, reaper *api.Reaper, cassdc *cassdcv1beta1.CassandraDatacenter) error {
-	if contains(reaper.Status.Clusters, cassdc.Spec.ClusterName) {
-		return nil
-	}
-
 	patch := client.MergeFrom(reaper.DeepCopy())
-	clusters := append(reaper.Status.Clusters, cassdc.Spec.ClusterName)
-	reaper.Status.Clusters = clusters
 
+	updated := false
+	if !contains(reaper.Status.Clusters, cassdc.Spec.ClusterName) {
+		reaper.Status.Clusters = append(reaper.Status.Clusters, cassdc.Spec.ClusterName)
+		updated = true
+	}
+	if SetCondition(&reaper.Status, newClustersRegisteredCondition(reaper)) {
+		updated = true
+	}
+
+	if !updated {
+		return nil
+	}
 	return s.Status().Patch(ctx, reaper, patch)
 }
 
-// Removes the cluster from .status.clusters if it is in the list. The status is patch
-// updated if the list is modified.
+// Removes the cluster from .status.clusters if it is in the list and updates the
+// ClustersRegistered condition. The status is patch updated if it is modified.
 func (s *StatusManager) RemoveClusterFromStatus(ctx context.Context, reaper *api.Reaper, cassdc *cassdcv1beta1.CassandraDatacenter) error {
 	if !contains(reaper.Status.Clusters, cassdc.Spec.ClusterName) {
 		return nil
@@ -51,10 +58,47 @@ func (s *StatusManager) RemoveClusterFromStatus(ctx context.Context, reaper *api
 
 	patch := client.MergeFrom(reaper.DeepCopy())
 	reaper.Status.Clusters = remove(reaper.Status.Clusters, cassdc.Spec.ClusterName)
+	SetCondition(&reaper.Status, newClustersRegisteredCondition(reaper))
 
 	return s.Status().Patch(ctx, reaper, patch)
 }
 
+// Replaces .status.clusters with the given clusters and updates the ClustersRegistered
+// condition. The status is patch updated if it is modified.
+func (s *StatusManager) SetClusters(ctx context.Context, reaper *api.Reaper, clusters []string) error {
+	if reflect.DeepEqual(reaper.Status.Clusters, clusters) {
+		return nil
+	}
+
+	patch := client.MergeFrom(reaper.DeepCopy())
+	reaper.Status.Clusters = clusters
+	SetCondition(&reaper.Status, newClustersRegisteredCondition(reaper))
+
+	return s.Status().Patch(ctx, reaper, patch)
+}
+
+// Sets .status of the RepairSchedule and patch the status.
+func (s *StatusManager) SetRepairScheduleStatus(ctx context.Context, schedule *api.RepairSchedule, status api.RepairScheduleStatus) error {
+	if schedule.Status == status {
+		return nil
+	}
+
+	patch := client.MergeFrom(schedule.DeepCopy())
+	schedule.Status = status
+	return s.Status().Patch(ctx, schedule, patch)
+}
+
+// Sets .status of the RepairRun and patch the status.
+func (s *StatusManager) SetRepairRunStatus(ctx context.Context, run *api.RepairRun, status api.RepairRunStatus) error {
+	if run.Status == status {
+		return nil
+	}
+
+	patch := client.MergeFrom(run.DeepCopy())
+	run.Status = status
+	return s.Status().Patch(ctx, run, patch)
+}
+
 func contains(slice []string, s string) bool {
 	for _, v := range slice {
 		if v == s {
@@ -68,7 +112,7 @@ func remove(slice []string, s string) []string {
 	newSlice := make([]string, 0)
 	for _, v := range slice {
 		if v != s {
-			newSlice = append(newSlice, s)
+			newSlice = append(newSlice, v)
 		}
 	}
 
@@ -15,7 +15,7 @@ type Annotated interface {
 const resourceHashAnnotationKey = "cassandra-reaper.io/resource-hash"
 
 func AddHashAnnotation(obj Annotated) {
-	hash := deepHashString(obj)
+	hash := DeepHashString(obj)
 	m := obj.GetAnnotations()
 	if m == nil {
 		m = map[string]string{}
@@ -33,7 +33,8 @@ func ResourcesHaveSameHash(r1, r2 Annotated) bool {
 	return a1[resourceHashAnnotationKey] == a2[resourceHashAnnotationKey]
 }
 
-func deepHashString(obj interface{}) string {
+// Returns a base64 encoded hash of the object. Map keys are sorted so the hash is stable.
+func DeepHashString(obj interface{}) string {
 	hasher := sha256.New()
 	hash.DeepHashObject(hasher, obj)
 	hashBytes := hasher.Sum([]byte{})
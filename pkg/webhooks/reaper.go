@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"github.com/thelastpickle/reaper-operator/pkg/config"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// The webhooks are registered as plain admission handlers rather than by implementing
+// webhook.Defaulter and webhook.Validator on the Reaper type since the validation logic
+// lives in pkg/config which already depends on the api package.
+
+// +kubebuilder:webhook:path=/mutate-reaper-cassandra-reaper-io-v1alpha1-reaper,mutating=true,failurePolicy=fail,groups=reaper.cassandra-reaper.io,resources=reapers,verbs=create;update,versions=v1alpha1,name=mreaper.kb.io
+// +kubebuilder:webhook:path=/validate-reaper-cassandra-reaper-io-v1alpha1-reaper,mutating=false,failurePolicy=fail,groups=reaper.cassandra-reaper.io,resources=reapers,verbs=create;update,versions=v1alpha1,name=vreaper.kb.io
+
+const (
+	ReaperDefaulterPath = "/mutate-reaper-cassandra-reaper-io-v1alpha1-reaper"
+	ReaperValidatorPath = "/validate-reaper-cassandra-reaper-io-v1alpha1-reaper"
+)
+
+// SetupReaperWebhooks registers the defaulting and validating webhooks for Reaper objects with
+// the manager's webhook server.
+func SetupReaperWebhooks(mgr ctrl.Manager, validator config.Validator) {
+	server := mgr.GetWebhookServer()
+	server.Register(ReaperDefaulterPath, &webhook.Admission{Handler: &reaperDefaulter{validator: validator}})
+	server.Register(ReaperValidatorPath, &webhook.Admission{Handler: &reaperValidator{validator: validator}})
+}
+
+type reaperDefaulter struct {
+	validator config.Validator
+
+	decoder *admission.Decoder
+}
+
+func (d *reaperDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+func (d *reaperDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	reaper := &api.Reaper{}
+	if err := d.decoder.Decode(req, reaper); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if !d.validator.SetDefaults(reaper) {
+		return admission.Allowed("")
+	}
+
+	marshaled, err := json.Marshal(reaper)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+type reaperValidator struct {
+	validator config.Validator
+
+	decoder *admission.Decoder
+}
+
+func (v *reaperValidator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+func (v *reaperValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	reaper := &api.Reaper{}
+	if err := v.decoder.Decode(req, reaper); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// The operator removes its finalizer from a Reaper that is being deleted, which has to
+	// succeed even if the spec does not pass the current validation.
+	if reaper.DeletionTimestamp != nil {
+		return admission.Allowed("reaper is being deleted")
+	}
+
+	var old *api.Reaper
+	if req.Operation == admissionv1beta1.Update {
+		old = &api.Reaper{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		// Updates of the metadata, e.g., of annotations and finalizers, are not validated.
+		if reflect.DeepEqual(old.Spec, reaper.Spec) {
+			return admission.Allowed("spec is unchanged")
+		}
+	}
+
+	if err := v.validator.Validate(reaper); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if old != nil {
+		if err := v.validator.ValidateUpdate(old, reaper); err != nil {
+			return admission.Denied(err.Error())
+		}
+	}
+
+	return admission.Allowed("")
+}
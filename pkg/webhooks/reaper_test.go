@@ -0,0 +1,121 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	api "github.com/thelastpickle/reaper-operator/api/v1alpha1"
+	"github.com/thelastpickle/reaper-operator/pkg/config"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newDecoder(t *testing.T) *admission.Decoder {
+	scheme := runtime.NewScheme()
+	if err := api.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to create scheme: %s", err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %s", err)
+	}
+	return decoder
+}
+
+func newRequest(t *testing.T, operation admissionv1beta1.Operation, reaper, old *api.Reaper) admission.Request {
+	req := admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{Operation: operation}}
+	raw, err := json.Marshal(reaper)
+	if err != nil {
+		t.Fatalf("failed to marshal reaper: %s", err)
+	}
+	req.Object.Raw = raw
+
+	if old != nil {
+		if req.OldObject.Raw, err = json.Marshal(old); err != nil {
+			t.Fatalf("failed to marshal reaper: %s", err)
+		}
+	}
+	return req
+}
+
+func newReaper(storageType api.StorageType, backend *api.CassandraBackend) *api.Reaper {
+	return &api.Reaper{
+		TypeMeta: metav1.TypeMeta{APIVersion: api.GroupVersion.String(), Kind: "Reaper"},
+		Spec: api.ReaperSpec{
+			ServerConfig: api.ServerConfig{
+				StorageType:      storageType,
+				CassandraBackend: backend,
+			},
+		},
+	}
+}
+
+func TestReaperDefaulter(t *testing.T) {
+	defaulter := &reaperDefaulter{validator: config.NewValidator(), decoder: newDecoder(t)}
+
+	resp := defaulter.Handle(context.Background(), newRequest(t, admissionv1beta1.Create, newReaper("", nil), nil))
+	if !resp.Allowed {
+		t.Fatalf("expected request to be allowed, got (%v)", resp.Result)
+	}
+	if len(resp.Patches) == 0 {
+		t.Errorf("expected defaults to be patched")
+	}
+}
+
+func TestReaperValidator(t *testing.T) {
+	validator := &reaperValidator{validator: config.NewValidator(), decoder: newDecoder(t)}
+	backend := &api.CassandraBackend{ClusterName: "test", CassandraService: "test-svc", Keyspace: "reaper_db"}
+
+	resp := validator.Handle(context.Background(), newRequest(t, admissionv1beta1.Create, newReaper(api.StorageTypeCassandra, nil), nil))
+	if resp.Allowed {
+		t.Errorf("expected create without cassandra backend to be denied")
+	}
+
+	resp = validator.Handle(context.Background(), newRequest(t, admissionv1beta1.Create, newReaper(api.StorageTypeCassandra, backend), nil))
+	if !resp.Allowed {
+		t.Errorf("expected valid create to be allowed, got (%v)", resp.Result)
+	}
+
+	changed := backend.DeepCopy()
+	changed.Keyspace = "reaper"
+	resp = validator.Handle(context.Background(), newRequest(t, admissionv1beta1.Update,
+		newReaper(api.StorageTypeCassandra, changed), newReaper(api.StorageTypeCassandra, backend)))
+	if resp.Allowed {
+		t.Errorf("expected keyspace change to be denied")
+	}
+}
+
+func TestReaperValidatorAllowsMetadataUpdates(t *testing.T) {
+	validator := &reaperValidator{validator: config.NewValidator(), decoder: newDecoder(t)}
+
+	// The Reaper was created before the webhook or before a validation rule was added.
+	invalid := newReaper(api.StorageTypeCassandra, nil)
+	annotated := invalid.DeepCopy()
+	annotated.Annotations = map[string]string{"reaper.cassandra-reaper.io/force-delete": "true"}
+
+	resp := validator.Handle(context.Background(), newRequest(t, admissionv1beta1.Update, annotated, invalid))
+	if !resp.Allowed {
+		t.Errorf("expected metadata update to be allowed, got (%v)", resp.Result)
+	}
+
+	deleted := invalid.DeepCopy()
+	now := metav1.Now()
+	deleted.DeletionTimestamp = &now
+	changed := deleted.DeepCopy()
+	changed.Spec.DeletionPolicy = api.DeletionPolicyRetain
+
+	resp = validator.Handle(context.Background(), newRequest(t, admissionv1beta1.Update, changed, deleted))
+	if !resp.Allowed {
+		t.Errorf("expected update of reaper that is being deleted to be allowed, got (%v)", resp.Result)
+	}
+
+	changed = invalid.DeepCopy()
+	changed.Spec.DeletionPolicy = api.DeletionPolicyRetain
+	resp = validator.Handle(context.Background(), newRequest(t, admissionv1beta1.Update, changed, invalid))
+	if resp.Allowed {
+		t.Errorf("expected spec update of invalid reaper to be denied")
+	}
+}